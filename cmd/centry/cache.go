@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/shell"
+)
+
+const noCacheFlag string = "--centry-no-cache"
+
+func initCacheFromEnvironment(runtime *Runtime) error {
+	if environmentOrDefault("CENTRY_NO_CACHE", "false") == "true" {
+		runtime.noCache = true
+		runtime.events = append(runtime.events, fmt.Sprintf("function cache disabled (source=%s)", "environment"))
+	}
+	return nil
+}
+
+// initCacheFromArgs looks for the no cache flag among the leading (global) flags, the flag itself is handled by the cli
+func initCacheFromArgs(runtime *Runtime) error {
+	i := findGlobalFlag(runtime.args, noCacheFlag, globalValueFlags(runtime.context.manifest))
+	if i < 0 {
+		return nil
+	}
+
+	if arg := runtime.args[i]; arg != noCacheFlag {
+		value, err := strconv.ParseBool(strings.TrimPrefix(arg, noCacheFlag+"="))
+		if err != nil {
+			return fmt.Errorf("invalid value for %s. %v", noCacheFlag, err)
+		}
+		if !value {
+			return nil
+		}
+	}
+
+	runtime.noCache = true
+	runtime.events = append(runtime.events, fmt.Sprintf("function cache disabled (source=%s)", "flag"))
+
+	return nil
+}
+
+// initFunctionCache sets up the disk cache used for functions parsed from scripts, unless disabled
+func initFunctionCache(runtime *Runtime) error {
+	if runtime.noCache {
+		return nil
+	}
+
+	dir, err := shell.DefaultFunctionCacheDir()
+	if err != nil {
+		runtime.events = append(runtime.events, fmt.Sprintf("function cache disabled, failed to resolve cache directory. %v", err))
+		return nil
+	}
+
+	runtime.context.functionCache = shell.NewFunctionCache(dir)
+	runtime.events = append(runtime.events, fmt.Sprintf("function cache enabled (path=%s)", dir))
+
+	return nil
+}
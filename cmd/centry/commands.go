@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
 	"github.com/kristofferahl/go-centry/internal/pkg/config"
@@ -37,6 +39,24 @@ func registerInternalCommands(runtime *Runtime) {
 				"command": "generate-markdown",
 			}),
 		}
+		validateCmd := &ValidateCommand{
+			Runtime: runtime,
+			Log: context.log.GetLogger().WithFields(logrus.Fields{
+				"command": "validate",
+			}),
+		}
+		explainCmd := &ExplainCommand{
+			Runtime: runtime,
+			Log: context.log.GetLogger().WithFields(logrus.Fields{
+				"command": "explain",
+			}),
+		}
+		doctorCmd := &DoctorCommand{
+			Runtime: runtime,
+			Log: context.log.GetLogger().WithFields(logrus.Fields{
+				"command": "doctor",
+			}),
+		}
 		internalCmd := withCommandDefaults(&cli.Command{
 			Name:      "internal",
 			Usage:     "Internal centry commands",
@@ -45,30 +65,99 @@ func registerInternalCommands(runtime *Runtime) {
 			Subcommands: []*cli.Command{
 				serveCmd.ToCLICommand(),
 				generateMarkdownCmd.ToCLICommand(),
+				validateCmd.ToCLICommand(),
+				explainCmd.ToCLICommand(),
+				doctorCmd.ToCLICommand(),
 			},
 		})
 		runtime.cli.Commands = append(runtime.cli.Commands, internalCmd)
 	}
 }
 
+// aliasRequest holds the aliases requested for a command until all commands have been registered
+type aliasRequest struct {
+	command *cli.Command
+	parent  *cli.Command
+	aliases []string
+}
+
+// scriptDiscoveryWorkers is the maximum number of scripts having their functions discovered concurrently
+const scriptDiscoveryWorkers int = 8
+
+// scriptDiscovery holds the functions discovered in the script of a command
+type scriptDiscovery struct {
+	command config.Command
+	script  shell.Script
+	funcs   []*shell.Function
+	err     error
+}
+
+// discoverScriptFunctions discovers the functions of the command scripts concurrently using a bounded pool of workers.
+// The results are returned in the same order as the commands.
+func discoverScriptFunctions(context *Context, commands []config.Command) []*scriptDiscovery {
+	discoveries := make([]*scriptDiscovery, len(commands))
+	for i, c := range commands {
+		discoveries[i] = &scriptDiscovery{
+			command: c,
+			script:  createScript(c, context),
+		}
+	}
+
+	jobs := make(chan *scriptDiscovery)
+	wg := sync.WaitGroup{}
+
+	workers := scriptDiscoveryWorkers
+	if len(discoveries) < workers {
+		workers = len(discoveries)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range jobs {
+				d.funcs, d.err = d.script.Functions()
+			}
+		}()
+	}
+
+	for _, d := range discoveries {
+		jobs <- d
+	}
+	close(jobs)
+	wg.Wait()
+
+	return discoveries
+}
+
 func registerManifestCommands(runtime *Runtime, options *cmd.OptionsSet) {
 	context := runtime.context
+	aliases := make([]aliasRequest, 0)
 
-	for _, cmd := range context.manifest.Commands {
-		cmd := cmd
-
+	commands := make([]config.Command, 0)
+	for _, cmd := range expandManifestCommands(runtime) {
 		if context.commandEnabledFunc != nil && context.commandEnabledFunc(cmd) == false {
 			continue
 		}
+		commands = append(commands, cmd)
+	}
+
+	// Only the scripts of the invoked command are loaded in lazy mode
+	commands, _ = lazyCommands(runtime, commands, options)
 
-		script := createScript(cmd, context)
+	// Functions are discovered concurrently while commands are registered in order, keeping registration and events deterministic
+	for _, discovery := range discoverScriptFunctions(context, commands) {
+		cmd := discovery.command
+		script := discovery.script
 
-		funcs, err := script.Functions()
+		funcs, err := discovery.funcs, discovery.err
 		if err != nil {
 			context.log.GetLogger().WithFields(logrus.Fields{
 				"command": cmd.Name,
 			}).Errorf("failed to parse script functions. %v", err)
 		} else {
+			groupAliases := scriptGroupAliases(script)
+
 			for _, fn := range funcs {
 				fn := fn
 				cmd := cmd
@@ -78,6 +167,9 @@ func registerManifestCommands(runtime *Runtime, options *cmd.OptionsSet) {
 					continue
 				}
 
+				mergeCommandOptions(runtime, cmd, fn)
+				applyOptionDefaults(runtime, fn.Options)
+
 				cmdDescription := cmd.Description
 				if fn.Description != "" {
 					cmd.Description = fn.Description
@@ -88,6 +180,13 @@ func registerManifestCommands(runtime *Runtime, options *cmd.OptionsSet) {
 					cmd.Help = fn.Help
 				}
 
+				// the category of the manifest command only applies to the top level command
+				cmdCategory := cmd.Category
+				cmd.Category = fn.Category
+				if fn.Category == "" && fn.Name == cmd.Name {
+					cmd.Category = cmdCategory
+				}
+
 				scriptCmd := &ScriptCommand{
 					Context:       context,
 					Log:           context.log.GetLogger().WithFields(logrus.Fields{}),
@@ -97,11 +196,13 @@ func registerManifestCommands(runtime *Runtime, options *cmd.OptionsSet) {
 					Function:      *fn,
 				}
 				cliCmd := scriptCmd.ToCLICommand()
+				runtime.commands[cliCmd] = scriptCmd
 
 				cmdKeyParts := scriptCmd.GetCommandInvocationPath()
 
 				var root *cli.Command
 				for depth, cmdKeyPart := range cmdKeyParts {
+					var parent *cli.Command
 					if depth == 0 {
 						if getCommand(runtime.cli.Commands, cmdKeyPart) == nil {
 							if depth == len(cmdKeyParts)-1 {
@@ -113,11 +214,13 @@ func registerManifestCommands(runtime *Runtime, options *cmd.OptionsSet) {
 									Name:      cmdKeyPart,
 									Usage:     cmdDescription,
 									UsageText: cmdHelp,
+									Category:  cmdCategory,
 									Action:    nil,
 								}))
 							}
 						}
 						root = getCommand(runtime.cli.Commands, cmdKeyPart)
+						aliases = append(aliases, aliasRequest{command: root, aliases: cmd.Aliases})
 					} else {
 						if getCommand(root.Subcommands, cmdKeyPart) == nil {
 							if depth == len(cmdKeyParts)-1 {
@@ -133,14 +236,140 @@ func registerManifestCommands(runtime *Runtime, options *cmd.OptionsSet) {
 								}))
 							}
 						}
+						parent = root
 						root = getCommand(root.Subcommands, cmdKeyPart)
 					}
+
+					if depth == len(cmdKeyParts)-1 {
+						aliases = append(aliases, aliasRequest{command: root, parent: parent, aliases: fn.Aliases})
+					} else {
+						group := strings.Join(cmdKeyParts[:depth+1], script.FunctionNamespaceSplitChar())
+						aliases = append(aliases, aliasRequest{command: root, parent: parent, aliases: groupAliases[group]})
+					}
 				}
 
 				runtime.events = append(runtime.events, fmt.Sprintf("registered command \"%s\"", scriptCmd.GetCommandInvocation()))
 			}
 		}
 	}
+
+	registerCommandAliases(runtime, aliases)
+}
+
+// scriptGroupAliases returns aliases annotated for placeholder (group) commands, keyed by function namespace
+func scriptGroupAliases(script shell.Script) map[string][]string {
+	aliases := make(map[string][]string)
+
+	annotations, err := script.FunctionAnnotations()
+	if err != nil {
+		return aliases
+	}
+
+	for _, a := range annotations {
+		if a.Namespace == config.CommandAnnotationCmdNamespace && a.Key == config.CommandAnnotationAliasesKey && a.NamespaceValues["cmd"] != "" {
+			aliases[a.NamespaceValues["cmd"]] = config.ParseAnnotationList(a.Value)
+		}
+	}
+
+	return aliases
+}
+
+// registerCommandAliases adds aliases to commands once all commands are registered,
+// rejecting aliases that collide with the name or alias of another command at the same level
+func registerCommandAliases(runtime *Runtime, requests []aliasRequest) {
+	logger := runtime.context.log.GetLogger()
+
+	for _, r := range requests {
+		siblings := runtime.cli.Commands
+		if r.parent != nil {
+			siblings = r.parent.Subcommands
+		}
+
+		for _, alias := range r.aliases {
+			existing := getCommand(siblings, alias)
+			if existing == r.command {
+				continue
+			}
+
+			if existing != nil {
+				logger.WithFields(logrus.Fields{
+					"command": r.command.Name,
+					"alias":   alias,
+				}).Errorf("alias collides with command \"%s\"", existing.Name)
+				continue
+			}
+
+			r.command.Aliases = append(r.command.Aliases, alias)
+			runtime.events = append(runtime.events, fmt.Sprintf("registered alias \"%s\" for command \"%s\"", alias, r.command.Name))
+		}
+	}
+}
+
+func expandManifestCommands(runtime *Runtime) []config.Command {
+	context := runtime.context
+
+	commands := make([]config.Command, 0)
+	for _, cmd := range context.manifest.Commands {
+		if !cmd.HasGlobPath() {
+			commands = append(commands, cmd)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(context.manifest.BasePath, cmd.Path))
+		if err != nil {
+			context.log.GetLogger().WithFields(logrus.Fields{
+				"path": cmd.Path,
+			}).Errorf("failed to expand command glob pattern. %v", err)
+			continue
+		}
+
+		for _, match := range matches {
+			path, err := filepath.Rel(context.manifest.BasePath, match)
+			if err != nil {
+				path = match
+			}
+
+			c := cmd
+			c.Path = path
+			c.Name = commandNameFromScript(c, context)
+
+			commands = append(commands, c)
+			runtime.events = append(runtime.events, fmt.Sprintf("discovered command \"%s\" (path=%s pattern=%s)", c.Name, c.Path, cmd.Path))
+		}
+	}
+
+	return commands
+}
+
+func commandNameFromScript(cmd config.Command, context *Context) string {
+	name := strings.TrimSuffix(filepath.Base(cmd.Path), filepath.Ext(cmd.Path))
+
+	annotations, err := createScript(cmd, context).FunctionAnnotations()
+	if err != nil {
+		return name
+	}
+
+	for _, a := range annotations {
+		if a.Namespace == config.CommandAnnotationCmdNamespace && len(a.NamespaceValues) == 0 && a.Key == config.CommandAnnotationNameKey {
+			return a.Value
+		}
+	}
+
+	return name
+}
+
+// resolveCommand walks the commands (and sub commands) named by the leading arguments.
+// Returns the last command found and the remaining arguments.
+func resolveCommand(commands []*cli.Command, args []string) (*cli.Command, []string) {
+	var found *cli.Command
+	for len(args) > 0 {
+		c := getCommand(commands, args[0])
+		if c == nil {
+			break
+		}
+		found, commands, args = c, c.Subcommands, args[1:]
+	}
+	return found, args
 }
 
 func getCommand(commands []*cli.Command, name string) *cli.Command {
@@ -160,9 +389,20 @@ func sortCommands(commands []*cli.Command) {
 }
 
 func createScript(cmd config.Command, context *Context) shell.Script {
+	dependencies := make([]string, 0)
+	for _, s := range context.manifest.Scripts {
+		if !filepath.IsAbs(s) {
+			s = filepath.Join(context.manifest.BasePath, s)
+		}
+		dependencies = append(dependencies, s)
+	}
+
 	return &shell.BashScript{
-		BasePath: context.manifest.BasePath,
-		Path:     cmd.Path,
+		BasePath:     context.manifest.BasePath,
+		Path:         cmd.Path,
+		Dependencies: dependencies,
+		Discovery:    context.manifest.Config.FunctionDiscovery,
+		Cache:        context.functionCache,
 		Log: context.log.GetLogger().WithFields(logrus.Fields{
 			"script": cmd.Path,
 		}),
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+	"github.com/urfave/cli/v2"
+)
+
+// completionLastArg returns the argument preceding the completion flag (see cli.DefaultCompleteWithFlags)
+func completionLastArg() string {
+	if len(os.Args) > 2 {
+		return os.Args[len(os.Args)-2]
+	}
+	return ""
+}
+
+// completeOptionValues writes the values of the enum option named by the argument, returns false when the argument is not an enum option
+func completeOptionValues(w io.Writer, arg string, set *cmd.OptionsSet) bool {
+	if !strings.HasPrefix(arg, "-") {
+		return false
+	}
+
+	name := strings.TrimLeft(arg, "-")
+	for _, o := range set.Sorted() {
+		if o.Type != cmd.EnumOption || (o.Name != name && o.Short != name) {
+			continue
+		}
+		for _, v := range o.Values {
+			fmt.Fprintln(w, v.Name)
+		}
+		return true
+	}
+
+	return false
+}
+
+// appCompletion completes the values of global enum options, falling back to the default completion of the cli
+func appCompletion(options *cmd.OptionsSet) cli.BashCompleteFunc {
+	return func(c *cli.Context) {
+		if completeOptionValues(c.App.Writer, completionLastArg(), options) {
+			return
+		}
+		cli.DefaultAppComplete(c)
+	}
+}
+
+// commandCompletion completes the values of command enum options, falling back to the default completion of the command
+func commandCompletion(command *cli.Command, options *cmd.OptionsSet) cli.BashCompleteFunc {
+	return func(c *cli.Context) {
+		if completeOptionValues(c.App.Writer, completionLastArg(), options) {
+			return
+		}
+		cli.DefaultCompleteWithFlags(command)(c)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/kristofferahl/go-centry/internal/pkg/config"
 	"github.com/kristofferahl/go-centry/internal/pkg/io"
 	"github.com/kristofferahl/go-centry/internal/pkg/log"
+	"github.com/kristofferahl/go-centry/internal/pkg/shell"
 )
 
 // Executor is the name of the executor
@@ -21,6 +22,10 @@ type Context struct {
 	io                 io.InputOutput
 	log                *log.Manager
 	manifest           *config.Manifest
+	profile            *config.Profile
+	userConfig         *config.UserConfig
+	functionCache      *shell.FunctionCache
+	lazy               bool
 	commandEnabledFunc func(config.Command) bool
 	optionEnabledFunc  func(config.Option) bool
 }
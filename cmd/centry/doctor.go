@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// DoctorCommand is a Command implementation that checks the tools required by commands
+type DoctorCommand struct {
+	Runtime *Runtime
+	Log     *logrus.Entry
+}
+
+// ToCLICommand returns a CLI command
+func (sc *DoctorCommand) ToCLICommand() *cli.Command {
+	return withCommandDefaults(&cli.Command{
+		Name:      "doctor",
+		Usage:     "Checks that the tools required by commands are installed",
+		UsageText: "",
+		Hidden:    false,
+		Action: func(c *cli.Context) error {
+			checks := sc.Run()
+			writeDoctorChecks(c.App.Writer, checks)
+
+			failed := 0
+			for _, check := range checks {
+				if check.Result.Err != nil {
+					failed++
+				}
+			}
+
+			if failed > 0 {
+				return cli.Exit(fmt.Sprintf("found %d missing or unsatisfied requirement(s)", failed), requirementsExitCode)
+			}
+
+			return nil
+		},
+	})
+}
+
+// doctorCheck holds the result of checking a requirement and what requires it
+type doctorCheck struct {
+	RequiredBy string
+	Result     *requirementResult
+}
+
+// Run checks the requirements of the config and all commands
+func (sc *DoctorCommand) Run() []doctorCheck {
+	sc.Log.Debugf("checking requirements")
+
+	context := sc.Runtime.context
+	checker := newRequirementChecker()
+	checks := make([]doctorCheck, 0)
+
+	add := func(requiredBy string, requirements []config.Requirement) {
+		for _, r := range requirements {
+			checks = append(checks, doctorCheck{RequiredBy: requiredBy, Result: checker.Check(r)})
+		}
+	}
+
+	add("config", context.manifest.Config.Requires)
+
+	for _, c := range expandManifestCommands(sc.Runtime) {
+		add(fmt.Sprintf("command %s", c.Name), c.Requires)
+
+		script := createScript(c, context)
+		funcs, err := script.Functions()
+		if err != nil {
+			sc.Log.WithFields(logrus.Fields{
+				"command": c.Name,
+			}).Errorf("failed to parse script functions. %v", err)
+			continue
+		}
+
+		for _, fn := range funcs {
+			if len(fn.Requires) == 0 {
+				continue
+			}
+			scriptCmd := &ScriptCommand{Command: c, Script: script, Function: *fn}
+			add(fmt.Sprintf("command %s", scriptCmd.GetCommandInvocation()), fn.Requires)
+		}
+	}
+
+	return checks
+}
+
+func writeDoctorChecks(w io.Writer, checks []doctorCheck) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REQUIREMENT\tREQUIRED BY\tSTATUS")
+
+	for _, check := range checks {
+		status := "ok"
+		if check.Result.Err != nil {
+			status = check.Result.Err.Error()
+		} else if check.Result.Version != "" {
+			status = fmt.Sprintf("ok (version=%s)", check.Result.Version)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", check.Result.Requirement, check.RequiredBy, status)
+	}
+
+	tw.Flush()
+}
@@ -3,7 +3,12 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
+	"github.com/kristofferahl/go-centry/internal/pkg/shell"
 )
 
 func environmentOrDefault(key string, defaultValue string) string {
@@ -24,3 +29,98 @@ func overrideFromEnvironment(runtime *Runtime) {
 		cli.Version = envVersion
 	}
 }
+
+// manifestToEnvVars resolves the environment variables declared in the manifest for a command.
+// Precedence (lowest to highest): config envFiles, config env, command envFiles, command env.
+func manifestToEnvVars(context *Context, command config.Command) ([]shell.EnvironmentVariable, error) {
+	manifest := context.manifest
+	env := make(map[string]string)
+
+	sources := []struct {
+		files []config.EnvFile
+		env   map[string]string
+	}{
+		{files: manifest.Config.EnvFiles, env: manifest.Config.Env},
+		{files: command.EnvFiles, env: command.Env},
+	}
+
+	for _, s := range sources {
+		for _, f := range s.files {
+			path := f.Path
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(manifest.BasePath, path)
+			}
+
+			values, err := config.ReadEnvFile(path)
+			if err != nil {
+				if os.IsNotExist(err) && f.Optional {
+					continue
+				}
+				if os.IsNotExist(err) {
+					return nil, fmt.Errorf("env file not found (path=%s)", f.Path)
+				}
+				return nil, err
+			}
+
+			for name, value := range values {
+				env[name] = value
+			}
+		}
+
+		for name, value := range s.env {
+			env[name] = value
+		}
+	}
+
+	envVars := make([]shell.EnvironmentVariable, 0)
+	for name, value := range env {
+		envVars = append(envVars, shell.EnvironmentVariable{
+			Name:  name,
+			Value: value,
+			Type:  shell.EnvironmentVariableTypeString,
+		})
+	}
+
+	return shell.SortEnvironmentVariables(envVars), nil
+}
+
+// quote returns the value as a single quoted shell string
+func quote(value string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", `'\''`))
+}
+
+// applyEnvironmentDefaults overrides the default values of options with the value of their input environment variable (FromEnv)
+func applyEnvironmentDefaults(runtime *Runtime, options *cmd.OptionsSet) {
+	for _, o := range options.Sorted() {
+		if o.FromEnv == "" {
+			continue
+		}
+
+		value := os.Getenv(o.FromEnv)
+		if value == "" {
+			continue
+		}
+
+		// Select options may be set using the name or the value of an option value
+		if o.Type == cmd.SelectOptionV2 && !o.HasValue(value) {
+			for _, v := range o.Values {
+				if v.ResolveValue() == value {
+					value = v.Name
+					break
+				}
+			}
+		}
+
+		err := options.SetDefault(o.Name, value)
+		if err != nil {
+			runtime.events = append(runtime.events, fmt.Sprintf("failed to apply environment value for option \"%s\" (env=%s), error: %v", o.Name, o.FromEnv, err))
+			continue
+		}
+
+		// A value provided by the environment satisfies a required option
+		o.Required = false
+		o.DefaultSource = fmt.Sprintf("environment (name=%s)", o.FromEnv)
+
+		runtime.events = append(runtime.events, fmt.Sprintf("applied environment value for option \"%s\" (env=%s set=%s)", o.Name, o.FromEnv, options.Name))
+	}
+}
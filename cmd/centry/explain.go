@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// ExplainCommand is a Command implementation that explains where the option values of a command come from
+type ExplainCommand struct {
+	Runtime *Runtime
+	Log     *logrus.Entry
+}
+
+// ToCLICommand returns a CLI command
+func (sc *ExplainCommand) ToCLICommand() *cli.Command {
+	return withCommandDefaults(&cli.Command{
+		Name:            "explain",
+		Usage:           "Explains where the option values of a command come from",
+		UsageText:       "internal explain <command path> [flags]",
+		Hidden:          false,
+		SkipFlagParsing: true,
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return cli.Exit("a command must be specified (usage: internal explain <command path> [flags])", 1)
+			}
+
+			command, args := resolveCommand(sc.Runtime.cli.Commands, c.Args().Slice())
+			script, ok := sc.Runtime.commands[command]
+			if !ok {
+				return cli.Exit(fmt.Sprintf("no command found to explain (args=%s)", strings.Join(c.Args().Slice(), " ")), 1)
+			}
+
+			sc.Log.Debugf("explaining command \"%s\" (args=%d)", script.Function.Name, len(args))
+
+			set, err := explainFlagSet(command, args)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("failed to parse flags (command=%s). %v", script.GetCommandInvocation(), err), usageExitCode)
+			}
+
+			// The global flags are looked up through the lineage of the explain command
+			explainOptions(c.App.Writer, cli.NewContext(c.App, set, c), script)
+			return nil
+		},
+	})
+}
+
+// explainFlagSet parses the arguments using the flags of the command
+func explainFlagSet(command *cli.Command, args []string) (*flag.FlagSet, error) {
+	set := flag.NewFlagSet(command.Name, flag.ContinueOnError)
+	set.SetOutput(ioutil.Discard)
+
+	for _, f := range command.Flags {
+		if err := f.Apply(set); err != nil {
+			return nil, err
+		}
+	}
+
+	return set, set.Parse(args)
+}
+
+// explainOptions writes a table of the environment variables set for the global and command options
+func explainOptions(w io.Writer, c *cli.Context, sc *ScriptCommand) {
+	prefix := sc.Context.manifest.Config.EnvironmentPrefix
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENVIRONMENT VARIABLE\tVALUE\tSOURCE\tOPTION\tPREFIX")
+
+	for _, set := range []*cmd.OptionsSet{sc.GlobalOptions, sc.Function.Options} {
+		for _, o := range set.Sorted() {
+			name := optionEnvName(o, prefix)
+			value := "(not set)"
+			if v, ok := optionToEnvVar(c, o, prefix, sc.Context.manifest.BasePath); ok && v.Value != "" {
+				value = v.Value
+				if v.IsList() || v.IsMap() {
+					value = strings.Join(v.Values, ",")
+				}
+				if v.Secret {
+					value = cmd.RedactedValue
+				}
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", name, value, optionValueSource(c, o), fmt.Sprintf("%s (set=%s)", o.Name, set.Name), optionPrefixHandling(o, prefix))
+		}
+	}
+
+	tw.Flush()
+}
+
+// optionValueSource returns where the value of an option came from
+func optionValueSource(c *cli.Context, o *cmd.Option) string {
+	switch o.Type {
+	case cmd.SelectOptionV2:
+		for _, v := range o.Values {
+			if c.IsSet(v.Name) {
+				return "flag"
+			}
+		}
+	default:
+		if c.IsSet(o.Name) {
+			return "flag"
+		}
+	}
+
+	if o.DefaultSource != "" {
+		return o.DefaultSource
+	}
+
+	return "default"
+}
+
+// optionPrefixHandling describes how the environment prefix is applied to an option
+func optionPrefixHandling(o *cmd.Option, prefix string) string {
+	switch {
+	case prefix == "":
+		return "none"
+	case o.Internal:
+		return "not prefixed (internal)"
+	default:
+		return fmt.Sprintf("prefixed (%s)", prefix)
+	}
+}
@@ -5,6 +5,13 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+// EnumOptionFlag is a string flag restricted to the values of an enum option
+type EnumOptionFlag struct {
+	cli.StringFlag
+
+	Values []cmd.OptionValue
+}
+
 type SelectOptionFlag struct {
 	cli.BoolFlag
 
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -31,7 +33,7 @@ func interactive(runtime *Runtime) {
 	if cmd != nil {
 		exec := false
 		confirm := &survey.Confirm{
-			Message: fmt.Sprintf("%s %s\n  would you like to run the command above:", runtime.cli.Name, strings.Join(rArgs, " ")),
+			Message: fmt.Sprintf("%s %s\n  would you like to run the command above:", runtime.cli.Name, strings.Join(redactSecretArgs(rArgs, append(runtime.cli.VisibleFlags(), cmd.VisibleFlags()...)), " ")),
 		}
 		survey.AskOne(confirm, &exec)
 
@@ -55,10 +57,16 @@ func checkStdin() error {
 func promptForCommands(parent *cli.Command, commands []*cli.Command, in []string) (cmd *cli.Command, args []string) {
 	descriptions := make(map[string]string)
 	values := make([]string, 0)
-	for _, c := range commands {
+	for _, c := range commandsByCategory(commands) {
 		if !c.Hidden {
 			values = append(values, c.Name)
 			descriptions[c.Name] = c.Usage
+			if len(c.Aliases) > 0 {
+				descriptions[c.Name] = fmt.Sprintf("%s (aliases: %s)", descriptions[c.Name], strings.Join(c.Aliases, ", "))
+			}
+			if c.Category != "" {
+				descriptions[c.Name] = fmt.Sprintf("[%s] %s", c.Category, descriptions[c.Name])
+			}
 		}
 	}
 
@@ -85,6 +93,15 @@ func promptForCommands(parent *cli.Command, commands []*cli.Command, in []string
 	return parent, append(in, reply)
 }
 
+// commandsByCategory returns the commands grouped by category, uncategorized commands first
+func commandsByCategory(commands []*cli.Command) []*cli.Command {
+	sorted := append([]*cli.Command{}, commands...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Category < sorted[j].Category
+	})
+	return sorted
+}
+
 func promptForOptions(flags []cli.Flag, in []string) []string {
 	handled := make(map[string]string)
 	values := make([]string, 0)
@@ -166,16 +183,52 @@ func appendFlagValue(name string, f cli.Flag, args []string) []string {
 		prompt := fmt.Sprintf("%soption \"%s\"", required, v.GroupName)
 		val := selectValue(prompt, values)
 		args = append(args, fmt.Sprintf("--%s", val))
+	case *SecretOptionFlag:
+		prompt := fmt.Sprintf("%soption \"%s\" (%s)", required, name, v.GetUsage())
+		val := enterSecret(prompt, v.IsRequired())
+		if val != "" {
+			args = append(args, fmt.Sprintf("--%s=%s", name, val))
+		}
+	case *EnumOptionFlag:
+		values := make(map[string]string)
+		for _, val := range v.Values {
+			values[val.Name] = fmt.Sprintf("%s (%s=%s)", v.GetUsage(), name, val.ResolveValue())
+		}
+		prompt := fmt.Sprintf("%soption \"%s\"", required, name)
+		val := selectValue(prompt, values)
+		args = append(args, fmt.Sprintf("--%s=%s", name, val))
 	case *cli.BoolFlag:
 		args = append(args, fmt.Sprintf("--%s", name))
 	case *cli.StringFlag:
 		prompt := fmt.Sprintf("%soption \"%s\" (%s)", required, name, v.GetUsage())
-		val := enterValue(prompt, v.GetValue())
+		val := ""
+		if v.TakesFile {
+			val = enterPath(prompt, v.GetValue())
+		} else {
+			val = enterValue(prompt, v.GetValue())
+		}
 		args = append(args, fmt.Sprintf("--%s=%s", name, val))
 	case *cli.IntFlag:
 		prompt := fmt.Sprintf("%soption \"%s\" (%s)", required, name, v.GetUsage())
 		val := enterValue(prompt, v.GetValue())
 		args = append(args, fmt.Sprintf("--%s=%s", name, val))
+	case *cli.Float64Flag:
+		prompt := fmt.Sprintf("%soption \"%s\" (%s)", required, name, v.GetUsage())
+		val := enterValue(prompt, v.GetValue())
+		args = append(args, fmt.Sprintf("--%s=%s", name, val))
+	case *cli.DurationFlag:
+		prompt := fmt.Sprintf("%soption \"%s\" (%s)", required, name, v.GetUsage())
+		val := enterValue(prompt, v.GetValue())
+		args = append(args, fmt.Sprintf("--%s=%s", name, val))
+	case *cli.StringSliceFlag:
+		def := ""
+		if v.Value != nil {
+			def = strings.Join(v.Value.Value(), ",")
+		}
+		prompt := fmt.Sprintf("%soption \"%s\" (%s, separate multiple values with a comma)", required, name, v.GetUsage())
+		for _, val := range trimEmpty(strings.Split(enterValue(prompt, def), ",")) {
+			args = append(args, fmt.Sprintf("--%s=%s", name, strings.TrimSpace(val)))
+		}
 	default:
 		panic(fmt.Errorf("unhnadled flag type, %v", f))
 	}
@@ -221,6 +274,45 @@ func enterValue(text string, def string) string {
 	return v
 }
 
+func enterSecret(text string, required bool) string {
+	v := ""
+	prompt := &survey.Password{
+		Message: fmt.Sprintf("enter a value for %s:", text),
+	}
+	if required {
+		survey.AskOne(prompt, &v, survey.WithValidator(survey.Required))
+	} else {
+		survey.AskOne(prompt, &v)
+	}
+	return v
+}
+
+func enterPath(text string, def string) string {
+	v := ""
+	prompt := &survey.Input{
+		Message: fmt.Sprintf("enter a path for %s:", text),
+		Default: def,
+		Suggest: suggestPaths,
+	}
+	if def == "" {
+		survey.AskOne(prompt, &v, survey.WithValidator(survey.Required))
+	} else {
+		survey.AskOne(prompt, &v)
+	}
+	return v
+}
+
+// suggestPaths returns the files and directories matching the path being completed, directories end with a separator
+func suggestPaths(toComplete string) []string {
+	matches, _ := filepath.Glob(toComplete + "*")
+	for i, m := range matches {
+		if fi, err := os.Stat(m); err == nil && fi.IsDir() {
+			matches[i] = m + string(filepath.Separator)
+		}
+	}
+	return matches
+}
+
 func trimEmpty(s []string) []string {
 	var r []string
 	for _, str := range s {
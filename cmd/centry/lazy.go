@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
+)
+
+const completionFlag string = "--generate-bash-completion"
+
+// lazyCommands returns the manifest commands matching the command invoked by the arguments.
+// Returns false when all commands must be registered (root help, completion, unknown commands etc.).
+func lazyCommands(runtime *Runtime, commands []config.Command, options *cmd.OptionsSet) ([]config.Command, bool) {
+	if !runtime.context.lazy {
+		return commands, false
+	}
+
+	for _, arg := range runtime.args {
+		if arg == completionFlag {
+			runtime.events = append(runtime.events, "lazy command registration disabled (reason=completion)")
+			return commands, false
+		}
+	}
+
+	name, ok := invokedCommandName(runtime.args, options)
+	if !ok {
+		runtime.events = append(runtime.events, "lazy command registration disabled (reason=no command)")
+		return commands, false
+	}
+
+	matches := make([]config.Command, 0)
+	for _, c := range commands {
+		if c.HasNameOrAlias(name) {
+			matches = append(matches, c)
+		}
+	}
+
+	if len(matches) == 0 {
+		runtime.events = append(runtime.events, fmt.Sprintf("lazy command registration disabled (reason=no matching command command=%s)", name))
+		return commands, false
+	}
+
+	runtime.events = append(runtime.events, fmt.Sprintf("lazy command registration (command=%s scripts=%d)", name, len(matches)))
+	return matches, true
+}
+
+// invokedCommandName returns the first argument that is not a global option (or the value of one).
+// Returns false when no command is invoked or an unknown flag makes it impossible to tell.
+func invokedCommandName(args []string, options *cmd.OptionsSet) (string, bool) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			return "", false
+		}
+
+		if !strings.HasPrefix(arg, "-") {
+			return arg, true
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+
+		o := findOption(options, name)
+		if o == nil {
+			return "", false
+		}
+
+		if optionTakesValue(o) {
+			i++
+		}
+	}
+
+	return "", false
+}
+
+// findOption returns the option with the given name or short name, including the values of select/v2 options
+func findOption(options *cmd.OptionsSet, name string) *cmd.Option {
+	for _, o := range options.Sorted() {
+		if o.Name == name || (o.Short != "" && o.Short == name) {
+			return o
+		}
+		if o.Type == cmd.SelectOptionV2 {
+			for _, v := range o.Values {
+				if v.Name == name || (v.Short != "" && v.Short == name) {
+					return o
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func optionTakesValue(o *cmd.Option) bool {
+	switch o.Type {
+	case cmd.StringOption, cmd.IntegerOption, cmd.FloatOption, cmd.DurationOption, cmd.ListOption, cmd.MapOption, cmd.PathOption, cmd.EnumOption:
+		return true
+	default:
+		return false
+	}
+}
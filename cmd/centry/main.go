@@ -14,6 +14,9 @@ func main() {
 	// Create the context
 	context := NewContext(CLI, io.Standard())
 
+	// Only load the scripts of the invoked command
+	context.lazy = true
+
 	// Create the runtime
 	runtime, err := NewRuntime(args, context)
 	if err != nil {
@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
 	"github.com/kristofferahl/go-centry/internal/pkg/config"
@@ -11,6 +13,9 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+// usageExitCode is the exit code used when the value of an option is not allowed (EX_USAGE)
+const usageExitCode int = 64
+
 func configureDefaultOptions() {
 	cli.HelpFlag = &cli.BoolFlag{
 		Name:    "help",
@@ -39,6 +44,23 @@ func createGlobalOptions(runtime *Runtime) *cmd.OptionsSet {
 		Hidden:      manifest.Config.HideInternalOptions,
 		Internal:    true,
 	})
+	options.Add(&cmd.Option{
+		Type:        cmd.BoolOption,
+		Name:        "centry-no-cache",
+		Description: "Disables the function cache",
+		FromEnv:     "CENTRY_NO_CACHE",
+		Default:     false,
+		Hidden:      manifest.Config.HideInternalOptions,
+		Internal:    true,
+	})
+	options.Add(&cmd.Option{
+		Type:        cmd.StringOption,
+		Name:        "centry-profile",
+		Description: "Activates a profile defined by the manifest",
+		FromEnv:     "CENTRY_PROFILE",
+		Hidden:      manifest.Config.HideInternalOptions,
+		Internal:    true,
+	})
 	options.Add(&cmd.Option{
 		Type:        cmd.BoolOption,
 		Name:        "centry-quiet",
@@ -56,17 +78,7 @@ func createGlobalOptions(runtime *Runtime) *cmd.OptionsSet {
 			continue
 		}
 
-		err := options.Add(&cmd.Option{
-			Type:        o.Type,
-			Name:        o.Name,
-			Short:       o.Short,
-			Description: o.Description,
-			EnvName:     o.EnvName,
-			Values:      mapOptionValuesToCmdOptionValues(o),
-			Default:     o.Default,
-			Required:    o.Required,
-			Hidden:      o.Hidden,
-		})
+		err := options.Add(mapOptionToCmdOption(o))
 
 		if err != nil {
 			runtime.events = append(runtime.events, fmt.Sprintf("failed to register global option \"%s\", error: %v", o.Name, err))
@@ -76,9 +88,20 @@ func createGlobalOptions(runtime *Runtime) *cmd.OptionsSet {
 		runtime.events = append(runtime.events, fmt.Sprintf("registered global option \"%s\"", o.Name))
 	}
 
+	// Applying defaults from the active profile, user config and environment
+	applyOptionDefaults(runtime, options)
+
 	return options
 }
 
+// applyOptionDefaults overrides the default values of options.
+// Precedence (lowest to highest): manifest default, profile, user config, environment (flags are applied by the cli).
+func applyOptionDefaults(runtime *Runtime, options *cmd.OptionsSet) {
+	applyProfileDefaults(runtime, options)
+	applyUserConfigDefaults(runtime, options)
+	applyEnvironmentDefaults(runtime, options)
+}
+
 func optionsSetToFlags(options *cmd.OptionsSet) []cli.Flag {
 	flags := make([]cli.Flag, 0)
 
@@ -103,9 +126,9 @@ func optionsSetToFlags(options *cmd.OptionsSet) []cli.Flag {
 				Hidden:   o.Hidden,
 			})
 		case cmd.SelectOptionV2:
-			def := false
+			def := ""
 			if o.Default != nil {
-				def = o.Default.(bool)
+				def = o.Default.(string)
 			}
 			for _, v := range o.Values {
 				short := []string{v.Short}
@@ -117,7 +140,7 @@ func optionsSetToFlags(options *cmd.OptionsSet) []cli.Flag {
 						Name:     v.Name,
 						Aliases:  short,
 						Usage:    o.Description,
-						Value:    def,
+						Value:    def == v.Name,
 						Required: false,
 						Hidden:   o.Hidden,
 					},
@@ -139,6 +162,45 @@ func optionsSetToFlags(options *cmd.OptionsSet) []cli.Flag {
 				Required: o.Required,
 				Hidden:   o.Hidden,
 			})
+		case cmd.FloatOption:
+			def := float64(0)
+			if o.Default != nil {
+				def = o.Default.(float64)
+			}
+			flags = append(flags, &cli.Float64Flag{
+				Name:     o.Name,
+				Aliases:  short,
+				Usage:    o.Description,
+				Value:    def,
+				Required: o.Required,
+				Hidden:   o.Hidden,
+			})
+		case cmd.DurationOption:
+			def := time.Duration(0)
+			if o.Default != nil {
+				def = o.Default.(time.Duration)
+			}
+			flags = append(flags, &cli.DurationFlag{
+				Name:     o.Name,
+				Aliases:  short,
+				Usage:    o.Description,
+				Value:    def,
+				Required: o.Required,
+				Hidden:   o.Hidden,
+			})
+		case cmd.ListOption, cmd.MapOption:
+			def := []string{}
+			if o.Default != nil {
+				def = o.Default.([]string)
+			}
+			flags = append(flags, &cli.StringSliceFlag{
+				Name:     o.Name,
+				Aliases:  short,
+				Usage:    o.Description,
+				Value:    cli.NewStringSlice(def...),
+				Required: o.Required,
+				Hidden:   o.Hidden,
+			})
 		case cmd.BoolOption:
 			def := false
 			if o.Default != nil {
@@ -157,13 +219,51 @@ func optionsSetToFlags(options *cmd.OptionsSet) []cli.Flag {
 			if o.Default != nil {
 				def = o.Default.(string)
 			}
-			flags = append(flags, &cli.StringFlag{
+			flag := cli.StringFlag{
 				Name:     o.Name,
 				Aliases:  short,
 				Usage:    o.Description,
 				Value:    def,
 				Required: o.Required,
 				Hidden:   o.Hidden,
+			}
+			if o.Secret {
+				if def != "" {
+					flag.DefaultText = cmd.RedactedValue
+				}
+				flags = append(flags, &SecretOptionFlag{StringFlag: flag})
+			} else {
+				flags = append(flags, &flag)
+			}
+		case cmd.EnumOption:
+			def := ""
+			if o.Default != nil {
+				def = o.Default.(string)
+			}
+			flags = append(flags, &EnumOptionFlag{
+				StringFlag: cli.StringFlag{
+					Name:     o.Name,
+					Aliases:  short,
+					Usage:    enumOptionUsage(o),
+					Value:    def,
+					Required: o.Required,
+					Hidden:   o.Hidden,
+				},
+				Values: o.Values,
+			})
+		case cmd.PathOption:
+			def := ""
+			if o.Default != nil {
+				def = o.Default.(string)
+			}
+			flags = append(flags, &cli.StringFlag{
+				Name:      o.Name,
+				Aliases:   short,
+				Usage:     o.Description,
+				Value:     def,
+				Required:  o.Required,
+				Hidden:    o.Hidden,
+				TakesFile: true,
 			})
 		default:
 			panic(fmt.Sprintf("option type \"%s\" not implemented", o.Type))
@@ -173,74 +273,254 @@ func optionsSetToFlags(options *cmd.OptionsSet) []cli.Flag {
 	return flags
 }
 
-func optionsSetToEnvVars(c *cli.Context, set *cmd.OptionsSet, prefix string) []shell.EnvironmentVariable {
+func optionsSetToEnvVars(c *cli.Context, set *cmd.OptionsSet, prefix string, basePath string) []shell.EnvironmentVariable {
 	envVars := make([]shell.EnvironmentVariable, 0)
 	for _, o := range set.Sorted() {
-		o := o
+		if v, ok := optionToEnvVar(c, o, prefix, basePath); ok {
+			envVars = append(envVars, v)
 
-		envName := o.EnvName
-		if envName == "" {
-			envName = o.Name
+			// Durations are also exported as whole seconds (<NAME>_SECONDS)
+			if o.Type == cmd.DurationOption {
+				envVars = append(envVars, shell.EnvironmentVariable{
+					Name:  v.Name + "_SECONDS",
+					Value: strconv.FormatInt(int64(c.Duration(o.Name).Seconds()), 10),
+					Type:  shell.EnvironmentVariableTypeInteger,
+				})
+			}
 		}
-		envName = strings.Replace(strings.ToUpper(envName), ".", "_", -1)
-		envName = strings.Replace(strings.ToUpper(envName), "-", "_", -1)
+	}
 
-		if prefix != "" && o.Internal == false {
-			envName = prefix + envName
-		}
+	return shell.SortEnvironmentVariables(envVars)
+}
 
-		value := c.String(o.Name)
+// optionToEnvVar returns the environment variable for an option, false when no variable is set for the option (unselected select options).
+// The base path of the manifest is used to resolve the default values of path options.
+func optionToEnvVar(c *cli.Context, o *cmd.Option, prefix string, basePath string) (shell.EnvironmentVariable, bool) {
+	envName := optionEnvName(o, prefix)
 
-		switch o.Type {
-		case cmd.StringOption:
-			envVars = append(envVars, shell.EnvironmentVariable{
+	value := c.String(o.Name)
+
+	switch o.Type {
+	case cmd.StringOption:
+		return shell.EnvironmentVariable{
+			Name:   envName,
+			Value:  value,
+			Type:   shell.EnvironmentVariableTypeString,
+			Secret: o.Secret,
+		}, true
+	case cmd.BoolOption:
+		return shell.EnvironmentVariable{
+			Name:  envName,
+			Value: value,
+			Type:  shell.EnvironmentVariableTypeBool,
+		}, true
+	case cmd.IntegerOption:
+		return shell.EnvironmentVariable{
+			Name:  envName,
+			Value: value,
+			Type:  shell.EnvironmentVariableTypeInteger,
+		}, true
+	case cmd.FloatOption:
+		return shell.EnvironmentVariable{
+			Name:  envName,
+			Value: value,
+			Type:  shell.EnvironmentVariableTypeFloat,
+		}, true
+	case cmd.DurationOption:
+		return shell.EnvironmentVariable{
+			Name:  envName,
+			Value: value,
+			Type:  shell.EnvironmentVariableTypeDuration,
+		}, true
+	case cmd.EnumOption:
+		for _, v := range o.Values {
+			if v.Name == value {
+				value = v.ResolveValue()
+				break
+			}
+		}
+		return shell.EnvironmentVariable{
+			Name:  envName,
+			Value: value,
+			Type:  shell.EnvironmentVariableTypeString,
+		}, true
+	case cmd.PathOption:
+		// Resolved before the script changes directory to the base path of the manifest,
+		// values that fail to resolve are rejected by validateOptionValues before the command runs
+		path, err := resolvePathOption(c, o, basePath)
+		if err != nil {
+			return shell.EnvironmentVariable{}, false
+		}
+		return shell.EnvironmentVariable{
+			Name:  envName,
+			Value: path,
+			Type:  shell.EnvironmentVariableTypeString,
+		}, true
+	case cmd.ListOption:
+		values := c.StringSlice(o.Name)
+		return shell.EnvironmentVariable{
+			Name:   envName,
+			Value:  strings.Join(values, "\n"),
+			Type:   shell.EnvironmentVariableTypeList,
+			Values: values,
+		}, true
+	case cmd.MapOption:
+		values := c.StringSlice(o.Name)
+		return shell.EnvironmentVariable{
+			Name:   envName,
+			Value:  strings.Join(values, "\n"),
+			Type:   shell.EnvironmentVariableTypeMap,
+			Values: values,
+		}, true
+	case cmd.SelectOption:
+		if value == "true" {
+			return shell.EnvironmentVariable{
 				Name:  envName,
-				Value: value,
+				Value: o.Name,
 				Type:  shell.EnvironmentVariableTypeString,
-			})
-		case cmd.BoolOption:
-			envVars = append(envVars, shell.EnvironmentVariable{
-				Name:  envName,
-				Value: value,
-				Type:  shell.EnvironmentVariableTypeBool,
-			})
-		case cmd.IntegerOption:
-			envVars = append(envVars, shell.EnvironmentVariable{
+			}, true
+		}
+	case cmd.SelectOptionV2:
+		value := ""
+		for _, v := range o.Values {
+			if v.Name == selectedOptionValue(c, o) {
+				value = v.ResolveValue()
+				break
+			}
+		}
+
+		if value != "" {
+			return shell.EnvironmentVariable{
 				Name:  envName,
 				Value: value,
-				Type:  shell.EnvironmentVariableTypeInteger,
-			})
-		case cmd.SelectOption:
-			if value == "true" {
-				envVars = append(envVars, shell.EnvironmentVariable{
-					Name:  envName,
-					Value: o.Name,
-					Type:  shell.EnvironmentVariableTypeString,
-				})
-			}
-		case cmd.SelectOptionV2:
-			value := ""
-			for _, v := range o.Values {
-				ov := c.String(v.Name)
-				if ov == "true" {
-					value = v.ResolveValue()
-					break
-				}
-			}
+				Type:  shell.EnvironmentVariableTypeString,
+			}, true
+		}
+	default:
+		panic(fmt.Sprintf("option type \"%s\" not implemented", o.Type))
+	}
 
-			if value != "" {
-				envVars = append(envVars, shell.EnvironmentVariable{
-					Name:  envName,
-					Value: value,
-					Type:  shell.EnvironmentVariableTypeString,
-				})
+	return shell.EnvironmentVariable{}, false
+}
+
+// optionEnvName returns the name of the environment variable used for an option
+func optionEnvName(o *cmd.Option, prefix string) string {
+	envName := o.EnvName
+	if envName == "" {
+		envName = o.Name
+	}
+	envName = strings.Replace(strings.ToUpper(envName), ".", "_", -1)
+	envName = strings.Replace(strings.ToUpper(envName), "-", "_", -1)
+
+	if prefix != "" && o.Internal == false {
+		envName = prefix + envName
+	}
+
+	return envName
+}
+
+// selectedOptionValues returns the names of the selected values of a select/v2 option.
+// Values set by flags take precedence over the default value.
+func selectedOptionValues(c *cli.Context, o *cmd.Option) []string {
+	selected := make([]string, 0)
+	for _, v := range o.Values {
+		if c.IsSet(v.Name) && c.Bool(v.Name) {
+			selected = append(selected, v.Name)
+		}
+	}
+
+	if len(selected) == 0 {
+		for _, v := range o.Values {
+			if c.Bool(v.Name) {
+				selected = append(selected, v.Name)
 			}
-		default:
-			panic(fmt.Sprintf("option type \"%s\" not implemented", o.Type))
 		}
 	}
 
-	return shell.SortEnvironmentVariables(envVars)
+	return selected
+}
+
+// selectedOptionValue returns the name of the first selected value of a select/v2 option
+func selectedOptionValue(c *cli.Context, o *cmd.Option) string {
+	selected := selectedOptionValues(c, o)
+	if len(selected) == 0 {
+		return ""
+	}
+	return selected[0]
+}
+
+// mergeCommandOptions merges the options declared for a command in the manifest with the options declared by annotations.
+// Options are matched by name where function options (manifest) take precedence over annotation options, which in turn
+// take precedence over command options (manifest).
+func mergeCommandOptions(runtime *Runtime, command config.Command, fn *shell.Function) {
+	overrides := command.Functions[fn.Name].Options
+	if len(command.Options) == 0 && len(overrides) == 0 {
+		return
+	}
+
+	options := cmd.NewOptionsSet(fn.Options.Name)
+
+	add := func(o *cmd.Option, source string) {
+		if options.Has(o.Name) {
+			runtime.events = append(runtime.events, fmt.Sprintf("skipped %s option \"%s\" for command \"%s\", overridden", source, o.Name, fn.Name))
+			return
+		}
+
+		if err := options.Add(o); err != nil {
+			runtime.events = append(runtime.events, fmt.Sprintf("failed to register %s option \"%s\" for command \"%s\", error: %v", source, o.Name, fn.Name, err))
+			return
+		}
+
+		runtime.events = append(runtime.events, fmt.Sprintf("registered %s option \"%s\" for command \"%s\"", source, o.Name, fn.Name))
+	}
+
+	for _, o := range overrides {
+		add(mapOptionToCmdOption(o), "function")
+	}
+	for _, o := range fn.Options.Sorted() {
+		add(o, "annotation")
+	}
+	for _, o := range command.Options {
+		add(mapOptionToCmdOption(o), "command")
+	}
+
+	fn.Options = options
+}
+
+// enumOptionUsage returns the usage of an enum option, listing the allowed values
+func enumOptionUsage(o *cmd.Option) string {
+	values := fmt.Sprintf("one of %s", strings.Join(o.ValueNames(), "|"))
+	if o.Description == "" {
+		return values
+	}
+	return fmt.Sprintf("%s (%s)", o.Description, values)
+}
+
+func mapOptionToCmdOption(o config.Option) *cmd.Option {
+	return &cmd.Option{
+		Type:        o.Type,
+		Name:        o.Name,
+		Short:       o.Short,
+		Description: o.Description,
+		EnvName:     o.EnvName,
+		FromEnv:     o.FromEnv,
+		Values:      mapOptionValuesToCmdOptionValues(o),
+		Default:     o.Default,
+		Required:    o.Required,
+		Secret:      o.Secret,
+		Hidden:      o.Hidden,
+		MustExist:   o.MustExist,
+		Kind:        o.Kind,
+		Extensions:  o.Extensions,
+		Rules: cmd.OptionRules{
+			Pattern:   o.Pattern,
+			Min:       o.Min,
+			Max:       o.Max,
+			MinLength: o.MinLength,
+			MaxLength: o.MaxLength,
+			OneOf:     o.OneOf,
+		},
+	}
 }
 
 func mapOptionValuesToCmdOptionValues(o config.Option) []cmd.OptionValue {
@@ -255,7 +535,44 @@ func mapOptionValuesToCmdOptionValues(o config.Option) []cmd.OptionValue {
 	return values
 }
 
-func validateOptionsSet(c *cli.Context, set *cmd.OptionsSet, cmdName string, level string, log *logrus.Entry) error {
+// resolvePathOption returns the absolute path of a path option, provided values are relative to the working directory and default values relative to the base path of the manifest
+func resolvePathOption(c *cli.Context, o *cmd.Option, basePath string) (string, error) {
+	if c.IsSet(o.Name) {
+		basePath = ""
+	}
+	return cmd.ResolvePath(c.String(o.Name), basePath)
+}
+
+// validateOptionValues returns an error if a value of the option is not allowed, options that were not set and have no default value are not validated
+func validateOptionValues(c *cli.Context, o *cmd.Option, basePath string) error {
+	if !c.IsSet(o.Name) && !o.HasDefault() {
+		return nil
+	}
+
+	values := []string{c.String(o.Name)}
+	switch o.Type {
+	case cmd.ListOption, cmd.MapOption:
+		values = c.StringSlice(o.Name)
+	case cmd.PathOption:
+		path, err := resolvePathOption(c, o, basePath)
+		if err != nil {
+			return fmt.Errorf("failed to resolve path \"%s\". %v", c.String(o.Name), err)
+		}
+		if err := o.ValidatePath(path); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range values {
+		if err := o.ValidateValue(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateOptionsSet(c *cli.Context, set *cmd.OptionsSet, cmdName string, level string, basePath string, log *logrus.Entry) error {
 	selectOptions := make(map[string][]string)
 	selectOptionRequired := make(map[string]bool)
 	selectOptionSelectedValues := make(map[string][]string)
@@ -284,9 +601,16 @@ func validateOptionsSet(c *cli.Context, set *cmd.OptionsSet, cmdName string, lev
 				selectOptions[group] = append(selectOptions[group], ov.Name)
 				v := c.String(ov.Name)
 				log.Debugf("found select option %s (group=%s value=%v required=%v)\n", ov.Name, group, v, o.Required)
-				if v == "true" {
-					selectOptionSelectedValues[group] = append(selectOptionSelectedValues[group], ov.Name)
-				}
+			}
+			if selected := selectedOptionValues(c, o); len(selected) > 0 {
+				selectOptionSelectedValues[group] = append(selectOptionSelectedValues[group], selected...)
+			}
+		case cmd.BoolOption:
+			// Booleans have no values to validate
+		default:
+			if err := validateOptionValues(c, o, basePath); err != nil {
+				cli.ShowCommandHelp(c, cmdName)
+				return cli.Exit(fmt.Sprintf("Invalid %s flag value for option \"%s\". %v", level, o.Name, err), usageExitCode)
 			}
 		}
 	}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+	"github.com/kristofferahl/go-centry/internal/pkg/shell"
+)
+
+const profileFlag string = "--centry-profile"
+
+func initProfileFromEnvironment(runtime *Runtime) error {
+	profile := environmentOrDefault("CENTRY_PROFILE", "")
+	if profile != "" {
+		runtime.profile = profile
+		runtime.events = append(runtime.events, fmt.Sprintf("profile set (name=%s source=%s)", runtime.profile, "environment"))
+	}
+	return nil
+}
+
+// initProfileFromArgs looks for the profile flag among the leading (global) flags, the flag itself is handled by the cli
+func initProfileFromArgs(runtime *Runtime) error {
+	args := runtime.args
+	i := findGlobalFlag(args, profileFlag, globalValueFlags(runtime.context.manifest))
+	if i < 0 {
+		return nil
+	}
+
+	runtime.profile = ""
+	if args[i] == profileFlag {
+		if len(args) > i+1 {
+			runtime.profile = args[i+1]
+		}
+	} else {
+		runtime.profile = strings.TrimPrefix(args[i], profileFlag+"=")
+	}
+
+	if runtime.profile == "" {
+		return fmt.Errorf("a value must be specified for %s", profileFlag)
+	}
+
+	runtime.events = append(runtime.events, fmt.Sprintf("profile set (name=%s source=%s)", runtime.profile, "flag"))
+
+	return nil
+}
+
+func activateProfile(runtime *Runtime) error {
+	if runtime.profile == "" {
+		return nil
+	}
+
+	manifest := runtime.context.manifest
+	profile := manifest.Profile(runtime.profile)
+	if profile == nil {
+		return fmt.Errorf("profile \"%s\" is not defined in the manifest (profiles=%s)", runtime.profile, strings.Join(manifest.ProfileNames(), ","))
+	}
+
+	runtime.context.profile = profile
+	runtime.events = append(runtime.events, fmt.Sprintf("activated profile \"%s\"", profile.Name))
+
+	return nil
+}
+
+// applyProfileDefaults overrides the default values of options with the defaults of the active profile
+func applyProfileDefaults(runtime *Runtime, options *cmd.OptionsSet) {
+	profile := runtime.context.profile
+	if profile == nil {
+		return
+	}
+
+	for _, o := range options.Sorted() {
+		value, ok := profile.Defaults[o.Name]
+		if !ok {
+			continue
+		}
+
+		err := options.SetDefault(o.Name, value)
+		if err != nil {
+			runtime.events = append(runtime.events, fmt.Sprintf("failed to apply profile default for option \"%s\" (profile=%s), error: %v", o.Name, profile.Name, err))
+			continue
+		}
+
+		// A value provided by the profile satisfies a required option
+		o.Required = false
+		o.DefaultSource = fmt.Sprintf("profile (name=%s)", profile.Name)
+
+		runtime.events = append(runtime.events, fmt.Sprintf("applied profile default for option \"%s\" (profile=%s set=%s)", o.Name, profile.Name, options.Name))
+	}
+}
+
+func profileToEnvVars(context *Context) []shell.EnvironmentVariable {
+	envVars := make([]shell.EnvironmentVariable, 0)
+	if context.profile == nil {
+		return envVars
+	}
+
+	for name, value := range context.profile.Env {
+		envVars = append(envVars, shell.EnvironmentVariable{
+			Name:  name,
+			Value: value,
+			Type:  shell.EnvironmentVariableTypeString,
+		})
+	}
+
+	return shell.SortEnvironmentVariables(envVars)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
+	"github.com/kristofferahl/go-centry/internal/pkg/shell"
+)
+
+// requirementsExitCode is the exit code used when required tools are missing (EX_UNAVAILABLE)
+const requirementsExitCode int = 69
+
+var versionPattern = regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
+
+// requirementResult holds the result of checking a requirement
+type requirementResult struct {
+	Requirement config.Requirement
+	Path        string
+	Version     string
+	Err         error
+}
+
+// requirementChecker checks requirements, caching the result of requirements checked more than once
+type requirementChecker struct {
+	results map[string]*requirementResult
+}
+
+func newRequirementChecker() *requirementChecker {
+	return &requirementChecker{
+		results: make(map[string]*requirementResult),
+	}
+}
+
+// Check returns the result of checking the requirement
+func (rc *requirementChecker) Check(r config.Requirement) *requirementResult {
+	key := fmt.Sprintf("%s|%s", r.String(), r.VersionCommand)
+	if result, ok := rc.results[key]; ok {
+		return result
+	}
+
+	result := &requirementResult{Requirement: r}
+	result.Path, result.Version, result.Err = checkRequirement(r)
+	rc.results[key] = result
+
+	return result
+}
+
+func checkRequirement(r config.Requirement) (path string, version string, err error) {
+	path, err = exec.LookPath(r.Name)
+	if err != nil {
+		return "", "", fmt.Errorf("required tool \"%s\" was not found in PATH", r.Name)
+	}
+
+	constraints, err := r.Constraints()
+	if err != nil {
+		return path, "", err
+	}
+	if constraints == nil {
+		return path, "", nil
+	}
+
+	versionCommand := r.VersionCommand
+	if versionCommand == "" {
+		versionCommand = fmt.Sprintf("%s --version", r.Name)
+	}
+
+	out, err := exec.Command("bash", "-c", versionCommand).CombinedOutput()
+	if err != nil {
+		return path, "", fmt.Errorf("failed to get the version of required tool \"%s\" (command=%s). %v", r.Name, versionCommand, err)
+	}
+
+	version = versionPattern.FindString(string(out))
+	if version == "" {
+		return path, "", fmt.Errorf("failed to find the version of required tool \"%s\" in the output of \"%s\"", r.Name, versionCommand)
+	}
+
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return path, version, fmt.Errorf("failed to parse the version of required tool \"%s\" (version=%s). %v", r.Name, version, err)
+	}
+
+	if !constraints.Check(v) {
+		return path, version, fmt.Errorf("required tool \"%s\" does not satisfy the version constraint (version=%s constraint=%s)", r.Name, version, r.Version)
+	}
+
+	return path, version, nil
+}
+
+// commandRequirements returns the requirements of the config, the command and the function
+func commandRequirements(manifest *config.Manifest, command config.Command, fn shell.Function) []config.Requirement {
+	requirements := make([]config.Requirement, 0)
+	requirements = append(requirements, manifest.Config.Requires...)
+	requirements = append(requirements, command.Requires...)
+	requirements = append(requirements, fn.Requires...)
+	return requirements
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/kristofferahl/go-centry/internal/pkg/config"
@@ -12,24 +14,34 @@ import (
 
 const metadataExitCode string = "exitcode"
 
+const defaultManifestFile string = "./centry.yaml"
+
+var manifestFileNames = []string{"centry.yaml", "centry.yml", "centry.json", "centry.toml"}
+
 // Runtime defines the runtime
 type Runtime struct {
-	cli     *cli.App
-	context *Context
-	file    string
-	args    []string
-	events  []string
+	cli      *cli.App
+	context  *Context
+	file     string
+	profile  string
+	noCache  bool
+	args     []string
+	events   []string
+	commands map[*cli.Command]*ScriptCommand
 }
 
 // NewRuntime builds a runtime based on the given arguments
 func NewRuntime(inputArgs []string, context *Context) (*Runtime, error) {
 	// Create the runtime
 	runtime := &Runtime{
-		cli:     nil,
-		context: context,
-		file:    "./centry.yaml",
-		args:    []string{},
-		events:  []string{},
+		cli:      nil,
+		context:  context,
+		file:     "",
+		profile:  "",
+		noCache:  false,
+		args:     []string{},
+		events:   []string{},
+		commands: make(map[*cli.Command]*ScriptCommand),
 	}
 
 	// Env manifest file
@@ -44,6 +56,24 @@ func NewRuntime(inputArgs []string, context *Context) (*Runtime, error) {
 		return nil, err
 	}
 
+	// Search parent directories for manifest file
+	err = initFromSearch(runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Env profile
+	err = initProfileFromEnvironment(runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Env function cache
+	err = initCacheFromEnvironment(runtime)
+	if err != nil {
+		return nil, err
+	}
+
 	// Load manifest
 	manifest, err := config.LoadManifest(runtime.file)
 	if err != nil {
@@ -51,6 +81,36 @@ func NewRuntime(inputArgs []string, context *Context) (*Runtime, error) {
 	}
 	context.manifest = manifest
 
+	// Args profile
+	err = initProfileFromArgs(runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Args function cache
+	err = initCacheFromArgs(runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Function cache
+	err = initFunctionCache(runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Activate profile
+	err = activateProfile(runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load user config
+	err = loadUserConfig(runtime)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the log manager
 	context.log = log.CreateManager(context.manifest.Config.Log.Level, context.manifest.Config.Log.Prefix, context.io)
 
@@ -74,6 +134,7 @@ func NewRuntime(inputArgs []string, context *Context) (*Runtime, error) {
 		HideHelpCommand:       true,
 		CustomAppHelpTemplate: cliHelpTemplate,
 		EnableBashCompletion:  true,
+		BashComplete:          appCompletion(options),
 
 		Writer:    context.io.Stdout,
 		ErrWriter: context.io.Stderr,
@@ -143,6 +204,96 @@ func initFromArgs(runtime *Runtime, inputArgs []string) error {
 	return nil
 }
 
+// findGlobalFlag returns the index of the flag (--<name> or --<name>=<value>) among the leading (global) flags of the arguments.
+// Values of global options are skipped, returns -1 when the flag is not found before the command, "--" or the first argument.
+func findGlobalFlag(args []string, flag string, valueFlags map[string]bool) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+
+		if arg == flag || strings.HasPrefix(arg, flag+"=") {
+			return i
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if !strings.Contains(name, "=") && valueFlags[name] {
+			i++
+		}
+	}
+
+	return -1
+}
+
+// globalValueFlags returns the names (and short names) of the global options that take a value
+func globalValueFlags(manifest *config.Manifest) map[string]bool {
+	flags := map[string]bool{
+		"centry-config-log-level": true,
+		"centry-profile":          true,
+	}
+
+	for _, mo := range manifest.Options {
+		o := mapOptionToCmdOption(mo)
+		if !optionTakesValue(o) {
+			continue
+		}
+
+		flags[o.Name] = true
+		if o.Short != "" {
+			flags[o.Short] = true
+		}
+	}
+
+	return flags
+}
+
+func initFromSearch(runtime *Runtime) error {
+	if runtime.file != "" {
+		return nil
+	}
+
+	runtime.file = defaultManifestFile
+
+	if environmentOrDefault("CENTRY_FILE_SEARCH", "true") == "false" {
+		runtime.events = append(runtime.events, "manifest file search disabled (source=environment)")
+		return nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		runtime.events = append(runtime.events, fmt.Sprintf("manifest file search failed, %v", err))
+		return nil
+	}
+
+	file := findManifestFile(wd)
+	if file != "" {
+		runtime.file = file
+		runtime.events = append(runtime.events, fmt.Sprintf("manifest file path set (path=%s source=%s)", runtime.file, "search"))
+	}
+
+	return nil
+}
+
+// findManifestFile looks for a manifest file in dir and all of it's parent directories
+func findManifestFile(dir string) string {
+	for {
+		for _, name := range manifestFileNames {
+			file := filepath.Join(dir, name)
+			if info, err := os.Stat(file); err == nil && !info.IsDir() {
+				return file
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
 // Execute runs the CLI and exits with a code
 func (runtime *Runtime) Execute() int {
 	args := append([]string{""}, runtime.args...)
@@ -2,13 +2,18 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	. "github.com/franela/goblin"
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
 	"github.com/kristofferahl/go-centry/internal/pkg/io"
 	test "github.com/kristofferahl/go-centry/internal/pkg/test"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
 )
 
 func TestMain(t *testing.T) {
@@ -17,6 +22,11 @@ func TestMain(t *testing.T) {
 	// Esuring the workdir is the root of the repo
 	os.Chdir("../../")
 
+	// Ensuring the function cache is not written to the cache directory of the user
+	cacheDir, _ := ioutil.TempDir("", "centry-cache")
+	os.Setenv("XDG_CACHE_HOME", cacheDir)
+	defer os.RemoveAll(cacheDir)
+
 	g.Describe("runtime", func() {
 		g.Describe("manifest file", func() {
 			g.It("tries to use ./centry.yaml as the default file", func() {
@@ -68,6 +78,70 @@ func TestMain(t *testing.T) {
 				g.Assert(err != nil).IsTrue("expected error, %v", err)
 				g.Assert(err.Error()).Eql("a value must be specified for --centry-file")
 			})
+
+			g.Describe("search", func() {
+				var wd, dir, file string
+
+				g.Before(func() {
+					wd, _ = os.Getwd()
+					dir, _ = ioutil.TempDir("", "centry-search-")
+					dir, _ = filepath.EvalSymlinks(dir)
+					file = filepath.Join(dir, "centry.yml")
+					ioutil.WriteFile(file, []byte("commands: []\nconfig:\n  name: search\n"), 0644)
+					os.MkdirAll(filepath.Join(dir, "a", "b", "c"), 0755)
+					os.Chdir(filepath.Join(dir, "a", "b", "c"))
+				})
+
+				g.After(func() {
+					os.Chdir(wd)
+					os.RemoveAll(dir)
+				})
+
+				g.It("finds the manifest file in a parent directory", func() {
+					context := NewContext(CLI, io.Headless())
+					runtime, err := NewRuntime([]string{}, context)
+					g.Assert(err == nil).IsTrue("expected error to be nil, %v", err)
+					g.Assert(runtime.file).Equal(file)
+					g.Assert(context.manifest.Config.Name).Equal("search")
+					test.AssertStringContains(g, strings.Join(runtime.events, "\n"), fmt.Sprintf("manifest file path set (path=%s source=search)", file))
+				})
+
+				g.It("does not search parent directories when disabled", func() {
+					os.Setenv("CENTRY_FILE_SEARCH", "false")
+					defer os.Unsetenv("CENTRY_FILE_SEARCH")
+					context := NewContext(CLI, io.Headless())
+					runtime, err := NewRuntime([]string{}, context)
+					g.Assert(runtime == nil).IsTrue("expected runtime to be nil, %v", runtime)
+					g.Assert(err != nil).IsTrue("expected error, %v", err)
+					g.Assert(err.Error()).Eql("manifest file not found (path=./centry.yaml)")
+				})
+			})
+		})
+	})
+
+	g.Describe("global flags", func() {
+		valueFlags := map[string]bool{"centry-config-log-level": true, "centry-profile": true, "S": true}
+
+		g.It("should find flag among the leading flags", func() {
+			g.Assert(findGlobalFlag([]string{"--centry-quiet", "--centry-profile", "p", "hello"}, "--centry-profile", valueFlags)).Equal(1)
+			g.Assert(findGlobalFlag([]string{"--centry-profile=p", "hello"}, "--centry-profile", valueFlags)).Equal(0)
+		})
+
+		g.It("should skip the values of flags taking a value", func() {
+			g.Assert(findGlobalFlag([]string{"--centry-config-log-level", "info", "--centry-profile", "p", "hello"}, "--centry-profile", valueFlags)).Equal(2)
+			g.Assert(findGlobalFlag([]string{"-S", "foo", "--centry-profile", "p", "hello"}, "--centry-profile", valueFlags)).Equal(2)
+			g.Assert(findGlobalFlag([]string{"--centry-config-log-level=info", "--centry-profile", "p"}, "--centry-profile", valueFlags)).Equal(1)
+		})
+
+		g.It("should find bool flags", func() {
+			g.Assert(findGlobalFlag([]string{"--centry-config-log-level", "info", "--centry-no-cache", "hello"}, "--centry-no-cache", valueFlags)).Equal(2)
+			g.Assert(findGlobalFlag([]string{"--centry-profile", "--centry-no-cache", "--centry-no-cache", "hello"}, "--centry-no-cache", valueFlags)).Equal(2)
+		})
+
+		g.It("should not find flag after the command or --", func() {
+			g.Assert(findGlobalFlag([]string{"hello", "--centry-profile", "p"}, "--centry-profile", valueFlags)).Equal(-1)
+			g.Assert(findGlobalFlag([]string{"--", "--centry-profile", "p"}, "--centry-profile", valueFlags)).Equal(-1)
+			g.Assert(findGlobalFlag([]string{"--centry-profile-name", "p"}, "--centry-profile", valueFlags)).Equal(-1)
 		})
 	})
 
@@ -90,6 +164,240 @@ func TestMain(t *testing.T) {
 			})
 		})
 
+		g.Describe("registering commands", func() {
+			g.It("should register commands in manifest order", func() {
+				registered := func() []string {
+					context := NewContext(CLI, io.Headless())
+					runtime, err := NewRuntime([]string{"--centry-file", defaultManifestPath, "--centry-no-cache"}, context)
+					test.AssertNoError(g, err)
+
+					events := make([]string, 0)
+					for _, e := range runtime.events {
+						if strings.HasPrefix(e, "registered command ") {
+							events = append(events, e)
+						}
+					}
+					return events
+				}
+
+				first := registered()
+				g.Assert(len(first) > 0).IsTrue("expected commands to be registered")
+				g.Assert(strings.HasPrefix(first[0], `registered command "scripttest`)).IsTrue(first[0])
+				g.Assert(strings.HasPrefix(first[len(first)-1], `registered command "helptest`)).IsTrue(first[len(first)-1])
+				g.Assert(registered()).Equal(first)
+			})
+		})
+
+		g.Describe("lazy command registration", func() {
+			lazyRuntime := func(args ...string) *Runtime {
+				context := NewContext(CLI, io.Headless())
+				context.lazy = true
+				runtime, err := NewRuntime(append([]string{"--centry-file", defaultManifestPath}, args...), context)
+				test.AssertNoError(g, err)
+				return runtime
+			}
+
+			commandNames := func(runtime *Runtime) []string {
+				names := make([]string, 0)
+				for _, c := range runtime.cli.Commands {
+					names = append(names, c.Name)
+				}
+				return names
+			}
+
+			g.It("should only register the invoked command", func() {
+				runtime := lazyRuntime("--centry-quiet", "--stringopt", "foo", "commandtest", "subcommand", "foo")
+				g.Assert(commandNames(runtime)).Equal([]string{"commandtest", "internal"})
+				test.AssertStringContains(g, strings.Join(runtime.events, "\n"), "lazy command registration (command=commandtest scripts=1)")
+			})
+
+			g.It("should register all commands when no command is invoked", func() {
+				runtime := lazyRuntime("--help")
+				g.Assert(len(commandNames(runtime)) > 2).IsTrue("expected all commands to be registered")
+			})
+
+			g.It("should register all commands for completion", func() {
+				runtime := lazyRuntime("commandtest", "--generate-bash-completion")
+				g.Assert(len(commandNames(runtime)) > 2).IsTrue("expected all commands to be registered")
+			})
+
+			g.It("should register all commands when the invoked command is unknown", func() {
+				var exitCode int
+				test.CaptureOutput(func() {
+					runtime := lazyRuntime("--centry-quiet", "commandnotdefined")
+					g.Assert(len(commandNames(runtime)) > 2).IsTrue("expected all commands to be registered")
+					exitCode = runtime.Execute()
+				})
+				g.Assert(exitCode).Equal(127)
+			})
+
+			g.It("should run the invoked command", func() {
+				var exitCode int
+				out := test.CaptureOutput(func() {
+					exitCode = lazyRuntime("--centry-quiet", "commandtest", "subcommand", "foo").Execute()
+				})
+				g.Assert(exitCode).Equal(0)
+				test.AssertStringContains(g, out.Stdout, "subcommand args (foo)")
+			})
+		})
+
+		g.Describe("invoking command discovered by static parsing", func() {
+			g.It("should have arguments passed", func() {
+				out := execQuiet("commandtest subcommand foo bar", "test/data/runtime_test_static_discovery.yaml")
+				test.AssertNoError(g, out.Error)
+				g.Assert(out.Stdout).Equal("subcommand args (foo bar)\n")
+			})
+
+			g.It("should have annotations applied", func() {
+				out := execQuiet("helptest subcommand --help", "test/data/runtime_test_static_discovery.yaml")
+				test.AssertNoError(g, out.Error)
+				test.AssertStringContains(g, out.Stdout, "--opt1 value, -o value")
+			})
+		})
+
+		g.Describe("invoking command from included manifest", func() {
+			g.It("should have arguments passed", func() {
+				expected := "include args (foo bar)"
+				out := execQuiet("includetest foo bar", "test/data/runtime_test_include.yaml")
+				test.AssertNoError(g, out.Error)
+				test.AssertStringContains(g, out.Stdout, expected)
+			})
+		})
+
+		g.Describe("invoking command discovered by glob pattern", func() {
+			g.It("should use the file name as command name", func() {
+				expected := "glob_one args (foo bar)"
+				out := execQuiet("glob_one foo bar", "test/data/runtime_test_glob.yaml")
+				test.AssertNoError(g, out.Error)
+				test.AssertStringContains(g, out.Stdout, expected)
+			})
+
+			g.It("should use the name annotation as command name", func() {
+				expected := "globtwo args (foo bar)"
+				out := execQuiet("globtwo foo bar", "test/data/runtime_test_glob.yaml")
+				test.AssertNoError(g, out.Error)
+				test.AssertStringContains(g, out.Stdout, expected)
+			})
+
+			g.It("should register sub commands", func() {
+				expected := "globtwo:sub args (foo bar)"
+				out := execQuiet("globtwo sub foo bar", "test/data/runtime_test_glob.yaml")
+				test.AssertNoError(g, out.Error)
+				test.AssertStringContains(g, out.Stdout, expected)
+			})
+
+			g.It("should display discovered commands in help", func() {
+				expected := `COMMANDS:
+   glob_one  Glob tests
+   globtwo   Named by annotation`
+				out := execQuiet("", "test/data/runtime_test_glob.yaml")
+				test.AssertStringContains(g, out.Stdout, expected)
+			})
+		})
+
+		g.Describe("invoking command by alias", func() {
+			aliasesManifestPath := "test/data/runtime_test_aliases.yaml"
+
+			g.It("should invoke command using manifest alias", func() {
+				out := execQuiet("at remove foo", aliasesManifestPath)
+				test.AssertStringContains(g, out.Stdout, "remove (foo)")
+			})
+
+			g.It("should invoke sub command using annotation alias", func() {
+				out := execQuiet("aliastest rm foo", aliasesManifestPath)
+				test.AssertStringContains(g, out.Stdout, "remove (foo)")
+
+				out = execQuiet("at del bar", aliasesManifestPath)
+				test.AssertStringContains(g, out.Stdout, "remove (bar)")
+			})
+
+			g.It("should invoke placeholder command using annotation alias", func() {
+				out := execQuiet("at g list foo", aliasesManifestPath)
+				test.AssertStringContains(g, out.Stdout, "group list (foo)")
+			})
+
+			g.It("should reject annotation aliases that collide with another command", func() {
+				out := execQuiet("at rm foo", aliasesManifestPath)
+				test.AssertStringContains(g, out.Stdout, "remove (foo)")
+
+				out = execQuiet("ac", aliasesManifestPath)
+				test.AssertStringContains(g, out.Stdout, "collision ()")
+			})
+
+			g.It("should display aliases in help", func() {
+				out := execQuiet("", aliasesManifestPath)
+				test.AssertStringContains(g, out.Stdout, `COMMANDS:
+   aliascollision, ac  Alias collision tests
+   aliastest, at       Alias tests`)
+
+				out = execQuiet("at --help", aliasesManifestPath)
+				test.AssertStringContains(g, out.Stdout, `COMMANDS:
+   group, g         ...
+   remove, rm, del  Removes things
+   trash            Trashes things`)
+			})
+		})
+
+		g.Describe("command options declared in the manifest", func() {
+			commandOptionsManifestPath := "test/data/runtime_test_command_options.yaml"
+
+			g.It("should use command options", func() {
+				out := execQuiet("manifestoptions plain", commandOptionsManifestPath)
+				test.AssertStringContains(g, out.Stdout, "level=command name=centry count=1")
+			})
+
+			g.It("should set command options from flags", func() {
+				out := execQuiet("manifestoptions plain -n foo --count=3", commandOptionsManifestPath)
+				test.AssertStringContains(g, out.Stdout, "level=command name=foo count=3")
+			})
+
+			g.It("should prefer annotation options over command options", func() {
+				out := execQuiet("manifestoptions annotated", commandOptionsManifestPath)
+				test.AssertStringContains(g, out.Stdout, "level=annotation name=centry count=1")
+			})
+
+			g.It("should prefer function options over annotation options", func() {
+				out := execQuiet("manifestoptions override", commandOptionsManifestPath)
+				test.AssertStringContains(g, out.Stdout, "level=function name=centry count=1")
+			})
+
+			g.It("should display options in help", func() {
+				out := execQuiet("manifestoptions override --help", commandOptionsManifestPath)
+				test.AssertStringContains(g, out.Stdout, `OPTIONS:
+   --count value           (default: 1)
+   --level value           Overridden level (default: "function")
+   --name value, -n value  (default: "centry")`)
+			})
+		})
+
+		g.Describe("command categories", func() {
+			categoriesManifestPath := "test/data/runtime_test_categories.yaml"
+
+			g.It("should group commands by category in help", func() {
+				out := execQuiet("", categoriesManifestPath)
+				test.AssertStringContains(g, out.Stdout, `COMMANDS:
+   commandtest  Command tests
+   Testing:
+     categorytest  Category tests`)
+			})
+
+			g.It("should group sub commands by category in help", func() {
+				out := execQuiet("categorytest --help", categoriesManifestPath)
+				test.AssertStringContains(g, out.Stdout, `COMMANDS:
+   clean  Cleans things
+   Development:
+     build  Builds things
+   Operations:
+     deploy  Deploys things`)
+			})
+
+			g.It("should display category in command help", func() {
+				out := execQuiet("categorytest build --help", categoriesManifestPath)
+				test.AssertStringContains(g, out.Stdout, `CATEGORY:
+   Development`)
+			})
+		})
+
 		g.Describe("invoking command that exits with a status code", func() {
 			g.It("should exit with exit code from command", func() {
 				out := execQuiet("commandtest exitcode")
@@ -224,6 +532,147 @@ func TestMain(t *testing.T) {
 				test.AssertStringContains(g, out.Stdout, expected)
 			})
 
+			g.It("should have environment variables set for float and duration options, durations normalized", func() {
+				out := execQuiet("optiontest numbers --floatopt=0.75 --durationopt=90s")
+
+				test.AssertStringHasKeyValue(g, out.Stdout, "FLOATOPT", "0.75")
+				test.AssertStringHasKeyValue(g, out.Stdout, "DURATIONOPT", "1m30s")
+				test.AssertStringHasKeyValue(g, out.Stdout, "DURATIONOPT_SECONDS", "90")
+			})
+
+			g.It("should have environment variables and arrays set for list and map options", func() {
+				out := execQuiet("optiontest collections --listopt=a --listopt=b,c --mapopt=env=prod --mapopt=team=x")
+
+				test.AssertStringHasKeyValue(g, out.Stdout, "LISTOPT_COUNT", "2")
+				test.AssertStringHasKeyValue(g, out.Stdout, "LISTOPT_0", "a")
+				test.AssertStringHasKeyValue(g, out.Stdout, "LISTOPT_1", "b,c")
+				test.AssertStringHasKeyValue(g, out.Stdout, "LISTOPT_LINES", "2")
+				test.AssertStringHasKeyValue(g, out.Stdout, "MAPOPT_env", "prod")
+				test.AssertStringHasKeyValue(g, out.Stdout, "MAPOPT_team", "x")
+			})
+
+			g.It("should use the default value of list options", func() {
+				out := execQuiet("optiontest collections")
+
+				test.AssertStringHasKeyValue(g, out.Stdout, "LISTOPT_COUNT", "2")
+				test.AssertStringHasKeyValue(g, out.Stdout, "LISTOPT_0", "x")
+				test.AssertStringHasKeyValue(g, out.Stdout, "LISTOPT_1", "y")
+			})
+
+			g.It("should fail for map option values that are not key=value", func() {
+				out := execCentry("optiontest collections --mapopt=invalid", false, defaultManifestPath)
+				assertUsageError(g, out, "Invalid command flag value for option \"mapopt\". invalid map value \"invalid\" (expected key=value)")
+			})
+
+			g.It("should have environment variables set to absolute paths for path options", func() {
+				out := execQuiet("optiontest path --fileopt=test/data/runtime_test.yaml --diropt=./test/data")
+
+				file, _ := filepath.Abs("test/data/runtime_test.yaml")
+				dir, _ := filepath.Abs("test/data")
+				test.AssertStringHasKeyValue(g, out.Stdout, "FILEOPT", file)
+				test.AssertStringHasKeyValue(g, out.Stdout, "DIROPT", dir)
+			})
+
+			g.It("should resolve default values of path options relative to the manifest", func() {
+				out := execQuiet("optiontest path")
+
+				dir, _ := filepath.Abs("test/data/commands")
+				test.AssertStringHasKeyValue(g, out.Stdout, "DIROPT", dir)
+			})
+
+			g.It("should fail for path option values that does not exist", func() {
+				out := execCentry("optiontest path --fileopt=test/data/missing.yaml", false, defaultManifestPath)
+				path, _ := filepath.Abs("test/data/missing.yaml")
+				assertUsageError(g, out, fmt.Sprintf("Invalid command flag value for option \"fileopt\". path \"%s\" does not exist", path))
+			})
+
+			g.It("should fail for path option values with an unexpected extension", func() {
+				out := execCentry("optiontest path --fileopt=test/data/manifest_test_valid.json", false, defaultManifestPath)
+				path, _ := filepath.Abs("test/data/manifest_test_valid.json")
+				assertUsageError(g, out, fmt.Sprintf("Invalid command flag value for option \"fileopt\". path \"%s\" must have one of the extensions yaml, yml", path))
+			})
+
+			g.It("should fail for path option values of the wrong kind", func() {
+				out := execCentry("optiontest path --diropt=test/data/runtime_test.yaml", false, defaultManifestPath)
+				path, _ := filepath.Abs("test/data/runtime_test.yaml")
+				assertUsageError(g, out, fmt.Sprintf("Invalid command flag value for option \"diropt\". path \"%s\" is not a directory", path))
+			})
+
+			g.It("should fail for path option values that can not be resolved", func() {
+				wd, _ := os.Getwd()
+				manifestPath, _ := filepath.Abs(defaultManifestPath)
+				dir, _ := ioutil.TempDir("", "centry-removed")
+				os.Chdir(dir)
+				os.RemoveAll(dir)
+				defer os.Chdir(wd)
+
+				out := execCentry("optiontest path --fileopt=runtime_test.yaml", false, manifestPath)
+				g.Assert(out.ExitCode).Equal(usageExitCode, out.Source)
+				test.AssertStringContains(g, out.Stderr, "Invalid command flag value for option \\\"fileopt\\\". failed to resolve path \\\"runtime_test.yaml\\\"")
+			})
+
+			g.It("should have environment variable set for enum options", func() {
+				out := execQuiet("optiontest enum --format=json")
+				test.AssertStringHasKeyValue(g, out.Stdout, "FORMAT", "json")
+
+				out = execQuiet("optiontest enum -f yaml")
+				test.AssertStringHasKeyValue(g, out.Stdout, "FORMAT", "yml")
+
+				out = execQuiet("optiontest enum")
+				test.AssertStringHasKeyValue(g, out.Stdout, "FORMAT", "table")
+			})
+
+			g.It("should fail for enum option values that are not allowed", func() {
+				out := execCentry("optiontest enum --format=xml", false, defaultManifestPath)
+				assertUsageError(g, out, "Invalid command flag value for option \"format\". value \"xml\" is not one of \" json | yaml | table \"")
+			})
+
+			g.It("should complete the values of enum options", func() {
+				out := execQuiet("optiontest enum --format --generate-bash-completion")
+				g.Assert(out.Stdout).Equal("json\nyaml\ntable\n")
+
+				out = execQuiet("optiontest enum -f --generate-bash-completion")
+				g.Assert(out.Stdout).Equal("json\nyaml\ntable\n")
+			})
+
+			g.It("should complete flags when the argument is not an enum option", func() {
+				out := execQuiet("optiontest enum --generate-bash-completion")
+				g.Assert(strings.Contains(out.Stdout, "json")).IsFalse("expected no enum values to be completed")
+			})
+
+			g.It("should display the values of enum options in help", func() {
+				out := execQuiet("optiontest enum --help")
+				test.AssertStringContains(g, out.Stdout, "Output format (one of json|yaml|table)")
+			})
+
+			g.It("should pass when option values satisfy the validation rules", func() {
+				out := execCentry("optiontest rules --port=8080 --name=web --env=prod --tag=v1 --tag=v2", true, defaultManifestPath)
+				g.Assert(out.ExitCode).Equal(0)
+				test.AssertStringHasKeyValue(g, out.Stdout, "PORT", "8080")
+				test.AssertStringHasKeyValue(g, out.Stdout, "NAME", "web")
+				test.AssertStringHasKeyValue(g, out.Stdout, "ENV", "prod")
+			})
+
+			g.It("should not validate options that are not set", func() {
+				out := execCentry("optiontest rules", true, defaultManifestPath)
+				g.Assert(out.ExitCode).Equal(0)
+			})
+
+			g.It("should fail with usage exit code when option values break the validation rules", func() {
+				for args, message := range map[string]string{
+					"--port=0":              `Invalid command flag value for option "port". value 0 is less than the minimum of 1`,
+					"--port=65536":          `Invalid command flag value for option "port". value 65536 is greater than the maximum of 65535`,
+					"--name=Web":            `Invalid command flag value for option "name". value "Web" does not match pattern "^[a-z]+$"`,
+					"--name=w":              `Invalid command flag value for option "name". value "w" is shorter than the minimum length of 2`,
+					"--name=webserver":      `Invalid command flag value for option "name". value "webserver" is longer than the maximum length of 8`,
+					"--env=test":            `Invalid command flag value for option "env". value "test" is not one of " dev | prod "`,
+					"--tag=v1 --tag=latest": `Invalid command flag value for option "tag". value "latest" does not match pattern "^v[0-9]+$"`,
+				} {
+					out := execCentry(fmt.Sprintf("optiontest rules %s", args), false, defaultManifestPath)
+					assertUsageError(g, out, message)
+				}
+			})
+
 			g.It("should have multipe environment variables set", func() {
 				out := execQuiet("--selectopt2 --stringopt=blazer --boolopt optiontest printenv")
 
@@ -271,6 +720,18 @@ func TestMain(t *testing.T) {
 					test.AssertStringContains(g, out.Stderr, "level=error msg=\"Required flag \\\"intopt\\\" not set\"")
 				})
 			})
+			g.Describe("of type float", func() {
+				g.It("should fail with error message", func() {
+					out := execCentry("optiontest numbers --durationopt=1m", false, "test/data/runtime_test.yaml")
+					test.AssertStringContains(g, out.Stderr, "level=error msg=\"Required flag \\\"floatopt\\\" not set\"")
+				})
+			})
+			g.Describe("of type duration", func() {
+				g.It("should fail with error message", func() {
+					out := execCentry("optiontest numbers --floatopt=0.5", false, "test/data/runtime_test.yaml")
+					test.AssertStringContains(g, out.Stderr, "level=error msg=\"Required flag \\\"durationopt\\\" not set\"")
+				})
+			})
 			g.Describe("of type select", func() {
 				g.It("should fail with error message", func() {
 					out := execCentry("optiontest required --stringopt=foo --boolopt --intopt=999 --selectopt_v2_1", false, "test/data/runtime_test.yaml")
@@ -353,6 +814,170 @@ func TestMain(t *testing.T) {
 		})
 	})
 
+	g.Describe("profiles", func() {
+		profilesManifestPath := "test/data/runtime_test_profiles.yaml"
+
+		g.Describe("without profile", func() {
+			g.It("should use manifest defaults", func() {
+				out := execQuiet("optiontest printenv", profilesManifestPath)
+				test.AssertStringHasKeyValue(g, out.Stdout, "STRINGOPT", "foobar")
+				test.AssertStringHasKeyValue(g, out.Stdout, "BOOLOPT", "false")
+				test.AssertStringHasKeyValue(g, out.Stdout, "INTOPT", "0")
+				g.Assert(strings.Contains(out.Stdout, "SELECTOPTV2=")).IsFalse()
+				g.Assert(strings.Contains(out.Stdout, "CENTRY_PROFILE=")).IsFalse()
+				g.Assert(strings.Contains(out.Stdout, "PROFILE_VAR=")).IsFalse()
+			})
+		})
+
+		g.Describe("with --centry-profile flag", func() {
+			g.It("should use profile defaults for global options", func() {
+				out := execQuiet("--centry-profile prod optiontest printenv", profilesManifestPath)
+				test.AssertNoError(g, out.Error)
+				test.AssertStringHasKeyValue(g, out.Stdout, "STRINGOPT", "prodstring")
+				test.AssertStringHasKeyValue(g, out.Stdout, "BOOLOPT", "true")
+				test.AssertStringHasKeyValue(g, out.Stdout, "INTOPT", "5")
+				test.AssertStringHasKeyValue(g, out.Stdout, "SELECTOPTV2", "value2")
+			})
+
+			g.It("should use profile defaults for command options", func() {
+				out := execQuiet("--centry-profile=prod commandtest options printenv", profilesManifestPath)
+				test.AssertNoError(g, out.Error)
+				test.AssertStringHasKeyValue(g, out.Stdout, "CMDSTRINGOPT", "cmdprod")
+			})
+
+			g.It("should let flags override profile defaults", func() {
+				out := execQuiet("--centry-profile prod --opt1 --stringopt=flagstring optiontest printenv", profilesManifestPath)
+				test.AssertNoError(g, out.Error)
+				test.AssertStringHasKeyValue(g, out.Stdout, "STRINGOPT", "flagstring")
+				test.AssertStringHasKeyValue(g, out.Stdout, "SELECTOPTV2", "value1")
+			})
+
+			g.It("should have profile environment variables set", func() {
+				out := execQuiet("--centry-profile prod optiontest printenv", profilesManifestPath)
+				test.AssertStringHasKeyValue(g, out.Stdout, "CENTRY_PROFILE", "prod")
+				test.AssertStringHasKeyValue(g, out.Stdout, "PROFILE_VAR", "prodvar")
+			})
+
+			g.It("should use profile when preceded by global options taking a value", func() {
+				out := execQuiet("--centry-config-log-level info --stringopt flagstring --centry-profile prod optiontest printenv", profilesManifestPath)
+				test.AssertNoError(g, out.Error)
+				test.AssertStringHasKeyValue(g, out.Stdout, "CENTRY_PROFILE", "prod")
+				test.AssertStringHasKeyValue(g, out.Stdout, "STRINGOPT", "flagstring")
+				test.AssertStringHasKeyValue(g, out.Stdout, "INTOPT", "5")
+			})
+
+			g.It("should not use profile flag after the command", func() {
+				out := execQuiet("--centry-config-log-level info optiontest printenv --centry-profile prod", profilesManifestPath)
+				g.Assert(strings.Contains(out.Stdout, "INTOPT=5")).IsFalse("expected profile not to be activated")
+			})
+
+			g.It("should give an error when profile is not defined", func() {
+				out := execQuiet("--centry-profile stage optiontest printenv", profilesManifestPath)
+				g.Assert(out.Error != nil).IsTrue("expected error")
+				g.Assert(out.Error.Error()).Equal("profile \"stage\" is not defined in the manifest (profiles=dev,prod)")
+			})
+
+			g.It("should give an error when flag is missing it's value", func() {
+				out := execQuiet("--centry-profile", profilesManifestPath)
+				g.Assert(out.Error != nil).IsTrue("expected error")
+				g.Assert(out.Error.Error()).Equal("a value must be specified for --centry-profile")
+			})
+		})
+
+		g.Describe("with CENTRY_PROFILE environment variable", func() {
+			g.It("should use profile", func() {
+				os.Setenv("CENTRY_PROFILE", "prod")
+				defer os.Unsetenv("CENTRY_PROFILE")
+
+				out := execQuiet("optiontest printenv", profilesManifestPath)
+				test.AssertStringHasKeyValue(g, out.Stdout, "STRINGOPT", "prodstring")
+				test.AssertStringHasKeyValue(g, out.Stdout, "CENTRY_PROFILE", "prod")
+			})
+
+			g.It("should be overridden by flag", func() {
+				os.Setenv("CENTRY_PROFILE", "prod")
+				defer os.Unsetenv("CENTRY_PROFILE")
+
+				out := execQuiet("--centry-profile dev optiontest printenv", profilesManifestPath)
+				test.AssertStringHasKeyValue(g, out.Stdout, "STRINGOPT", "foobar")
+				test.AssertStringHasKeyValue(g, out.Stdout, "CENTRY_PROFILE", "dev")
+			})
+		})
+	})
+
+	g.Describe("option values from environment and user config", func() {
+		fromEnvManifestPath := "test/data/runtime_test_from_env.yaml"
+
+		g.Describe("without user config", func() {
+			g.AfterEach(func() {
+				os.Unsetenv("CENTRY_TEST_CONTEXT")
+				os.Unsetenv("CENTRY_TEST_LEVEL")
+				os.Unsetenv("CENTRY_TEST_NAME")
+			})
+
+			g.It("should fail when required options are not set", func() {
+				out := execWithLogging("fromenvtest", fromEnvManifestPath)
+				test.AssertStringContains(g, out.Stderr, "level=error msg=\"Required flag \\\"name\\\" not set\"")
+
+				os.Setenv("CENTRY_TEST_NAME", "foo")
+				out = execWithLogging("fromenvtest", fromEnvManifestPath)
+				test.AssertStringContains(g, out.Stderr, "level=error msg=\"Required global flag missing for select option group \\\"context\\\"")
+			})
+
+			g.It("should set required options from environment", func() {
+				os.Setenv("CENTRY_TEST_CONTEXT", "development")
+				os.Setenv("CENTRY_TEST_NAME", "foo")
+				out := execQuiet("fromenvtest", fromEnvManifestPath)
+				test.AssertStringContains(g, out.Stdout, "context=dev level=manifest name=foo")
+			})
+
+			g.It("should set select option from environment using the value", func() {
+				os.Setenv("CENTRY_TEST_CONTEXT", "prod")
+				os.Setenv("CENTRY_TEST_NAME", "foo")
+				out := execQuiet("fromenvtest", fromEnvManifestPath)
+				test.AssertStringContains(g, out.Stdout, "context=prod level=manifest name=foo")
+			})
+
+			g.It("should prefer flags over environment", func() {
+				os.Setenv("CENTRY_TEST_CONTEXT", "development")
+				os.Setenv("CENTRY_TEST_LEVEL", "env")
+				os.Setenv("CENTRY_TEST_NAME", "foo")
+				out := execQuiet("--production --level=flag fromenvtest --name=bar", fromEnvManifestPath)
+				test.AssertStringContains(g, out.Stdout, "context=prod level=flag name=bar")
+			})
+		})
+
+		g.Describe("with user config", func() {
+			g.BeforeEach(func() {
+				wd, _ := os.Getwd()
+				os.Setenv("XDG_CONFIG_HOME", filepath.Join(wd, "test/data/userconfig"))
+				os.Setenv("CENTRY_TEST_NAME", "foo")
+			})
+
+			g.AfterEach(func() {
+				os.Unsetenv("XDG_CONFIG_HOME")
+				os.Unsetenv("CENTRY_TEST_LEVEL")
+				os.Unsetenv("CENTRY_TEST_NAME")
+			})
+
+			g.It("should set options from user config", func() {
+				out := execQuiet("fromenvtest", fromEnvManifestPath)
+				test.AssertStringContains(g, out.Stdout, "context=prod level=user name=foo")
+			})
+
+			g.It("should prefer environment over user config", func() {
+				os.Setenv("CENTRY_TEST_LEVEL", "env")
+				out := execQuiet("fromenvtest", fromEnvManifestPath)
+				test.AssertStringContains(g, out.Stdout, "context=prod level=env name=foo")
+			})
+
+			g.It("should prefer flags over user config", func() {
+				out := execQuiet("--development --level=flag fromenvtest", fromEnvManifestPath)
+				test.AssertStringContains(g, out.Stdout, "context=dev level=flag name=foo")
+			})
+		})
+	})
+
 	g.Describe("environment", func() {
 		g.Describe("centry environment variables", func() {
 			g.It("should have environment variables set", func() {
@@ -364,6 +989,291 @@ func TestMain(t *testing.T) {
 				test.AssertStringHasKeyValue(g, out.Stdout, "CENTRY_COMMAND_NAME", "optiontest")
 			})
 		})
+
+		g.Describe("manifest environment variables", func() {
+			envManifestPath := "test/data/runtime_test_env.yaml"
+
+			g.It("should have environment variables from env and env files set", func() {
+				out := execQuiet("envtest", envManifestPath)
+				test.AssertStringHasKeyValue(g, out.Stdout, "GLOBAL_FILE", "global-file")
+				test.AssertStringHasKeyValue(g, out.Stdout, "GLOBAL_ENV", "global-env")
+				test.AssertStringHasKeyValue(g, out.Stdout, "COMMAND_FILE", "command file")
+				test.AssertStringHasKeyValue(g, out.Stdout, "COMMAND_ENV", "command-env")
+			})
+
+			g.It("should apply environment variables in order of precedence", func() {
+				out := execQuiet("envtest", envManifestPath)
+				test.AssertStringHasKeyValue(g, out.Stdout, "OVERRIDDEN_BY_GLOBAL_ENV", "global-env")
+				test.AssertStringHasKeyValue(g, out.Stdout, "OVERRIDDEN_BY_COMMAND_FILE", "command-file")
+				test.AssertStringHasKeyValue(g, out.Stdout, "OVERRIDDEN_BY_COMMAND_ENV", "command-env")
+			})
+
+			g.It("should escape quotes in values", func() {
+				out := execQuiet("envtest", envManifestPath)
+				test.AssertStringHasKeyValue(g, out.Stdout, "QUOTED", `it's "quoted"`)
+			})
+
+			g.It("should fail when a required env file is missing", func() {
+				out := execQuiet("envmissing", envManifestPath)
+				g.Assert(out.ExitCode).Equal(1)
+			})
+		})
+	})
+
+	g.Describe("internal validate", func() {
+		g.It("should exit with status code 0 when no problems are found", func() {
+			out := execQuiet("internal validate")
+			test.AssertNoError(g, out.Error)
+			g.Assert(out.ExitCode).Equal(0)
+			g.Assert(out.Stdout).Equal("")
+		})
+
+		g.It("should exit with status code 1 and report problems", func() {
+			expected := `commands/validate/validate_collision.sh: command "validatetest run" is also defined by commands/validate/validate_test.sh (function=validatetest:run)
+commands/validate/validate_manifest_script.sh:5: syntax error: unexpected end of file
+commands/validate/validate_syntax_error.sh:6: syntax error near unexpected token ` + "`}'" + `
+commands/validate/validate_test.sh:4: duplicate short name "f" (options=first,second)
+commands/validate/validate_test.sh:5: environment variable "FIRST" is used by multiple options (options=first,third)
+commands/validate/validate_test.sh:6: environment variable "GLOBALOPT" of option "fourth" collides with global option "globalopt"
+commands/validate/validate_test.sh:7: unknown annotation key "unknown" (namespace=centry.cmd.option)
+commands/validate/validate_test.sh:8: invalid min for option "fifth", must be an integer (value=abc)
+commands/validate/validate_test.sh:9: invalid path kind "socket" (option=sixth)
+commands/validate/validate_test.sh:14: annotation refers to function "validatetest:missing" that does not exist
+commands/validate/validate_test.sh:19: invalid version constraint for requirement "bash" (version=~>nope). improper constraint: ~>nope
+runtime_test_validate.yaml: unknown annotation key "unknown" (namespace=centry.api)
+runtime_test_validate.yaml: min and max are only supported by integer options (option=minopt type=string)
+runtime_test_validate.yaml: duplicate short name "g" (options=globalopt,otheropt)
+`
+			out := execQuiet("internal validate", "test/data/runtime_test_validate.yaml")
+			g.Assert(out.ExitCode).Equal(1)
+
+			problems := make([]string, 0)
+			test.CaptureOutput(func() {
+				context := NewContext(CLI, io.Headless())
+				runtime, err := NewRuntime([]string{"--centry-file", "test/data/runtime_test_validate.yaml"}, context)
+				test.AssertNoError(g, err)
+
+				validateCmd := &ValidateCommand{Runtime: runtime, Log: context.log.GetLogger().WithFields(logrus.Fields{})}
+				for _, p := range validateCmd.Run() {
+					problems = append(problems, p.String()+"\n")
+				}
+			})
+			g.Assert(strings.Join(problems, "")).Equal(expected)
+		})
+	})
+
+	g.Describe("function cache", func() {
+		var dir string
+		var manifestPath string
+
+		writeScript := func(functions ...string) {
+			script := "#!/usr/bin/env bash\n"
+			for _, fn := range functions {
+				script += fmt.Sprintf("cachetest:%s() {\n  echo \"%s\"\n}\n", fn, fn)
+			}
+			ioutil.WriteFile(filepath.Join(dir, "cache_test.sh"), []byte(script), 0o644)
+		}
+
+		cacheEntries := func() []string {
+			files, _ := filepath.Glob(filepath.Join(os.Getenv("XDG_CACHE_HOME"), "centry", "functions", "*.json"))
+			return files
+		}
+
+		g.BeforeEach(func() {
+			dir, _ = ioutil.TempDir("", "centry-cache-test")
+			os.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+			manifestPath = filepath.Join(dir, "centry.yaml")
+			ioutil.WriteFile(manifestPath, []byte("commands:\n  - name: cachetest\n    path: cache_test.sh\nconfig:\n  name: centry\n  log:\n    level: debug\n"), 0o644)
+			writeScript("first")
+		})
+
+		g.AfterEach(func() {
+			os.Setenv("XDG_CACHE_HOME", cacheDir)
+			os.RemoveAll(dir)
+		})
+
+		g.It("should cache functions parsed from scripts", func() {
+			out := execWithLogging("cachetest first", manifestPath)
+			g.Assert(out.Stdout).Equal("first\n")
+			g.Assert(strings.Contains(out.Stderr, "loaded functions from cache")).IsFalse("expected functions not to be loaded from cache")
+			g.Assert(len(cacheEntries())).Equal(1)
+
+			out = execWithLogging("cachetest first", manifestPath)
+			g.Assert(out.Stdout).Equal("first\n")
+			test.AssertStringContains(g, out.Stderr, "loaded functions from cache")
+		})
+
+		g.It("should invalidate cached functions when the script changes", func() {
+			execQuiet("cachetest first", manifestPath)
+			writeScript("first", "second")
+
+			out := execQuiet("cachetest second", manifestPath)
+			g.Assert(out.Stdout).Equal("second\n")
+			g.Assert(len(cacheEntries())).Equal(1)
+		})
+
+		g.It("should bypass the cache when --centry-no-cache is set", func() {
+			out := execWithLogging("--centry-no-cache cachetest first", manifestPath)
+			g.Assert(out.Stdout).Equal("first\n")
+			g.Assert(strings.Contains(out.Stderr, "loaded functions from cache")).IsFalse("expected functions not to be loaded from cache")
+			g.Assert(len(cacheEntries())).Equal(0)
+		})
+
+		g.It("should bypass the cache when --centry-no-cache is preceded by global options taking a value", func() {
+			out := execWithLogging("--centry-config-log-level debug --centry-no-cache cachetest first", manifestPath)
+			g.Assert(out.Stdout).Equal("first\n")
+			g.Assert(len(cacheEntries())).Equal(0)
+		})
+
+		g.It("should use the cache when --centry-no-cache=false is set", func() {
+			out := execQuiet("--centry-no-cache=false cachetest first", manifestPath)
+			g.Assert(out.Stdout).Equal("first\n")
+			g.Assert(len(cacheEntries())).Equal(1)
+		})
+	})
+
+	g.Describe("requirements", func() {
+		requirementsManifestPath := "test/data/runtime_test_requirements.yaml"
+
+		g.It("should run command when requirements are satisfied", func() {
+			out := execQuiet("requirestest ok", requirementsManifestPath)
+			g.Assert(out.ExitCode).Equal(0)
+			test.AssertStringContains(g, out.Stdout, "ok ()")
+		})
+
+		g.It("should exit with requirements exit code when a required tool is missing", func() {
+			out := execQuiet("requirestest missing", requirementsManifestPath)
+			g.Assert(out.ExitCode).Equal(requirementsExitCode)
+
+			result := newRequirementChecker().Check(config.Requirement{Name: "centry-missing-tool"})
+			g.Assert(result.Err.Error()).Equal(`required tool "centry-missing-tool" was not found in PATH`)
+		})
+
+		g.It("should exit with requirements exit code when a version constraint is not satisfied", func() {
+			out := execQuiet("requirestest version", requirementsManifestPath)
+			g.Assert(out.ExitCode).Equal(requirementsExitCode)
+
+			result := newRequirementChecker().Check(config.Requirement{Name: "bash", Version: "<2"})
+			test.AssertStringContains(g, result.Err.Error(), `required tool "bash" does not satisfy the version constraint`)
+		})
+	})
+
+	g.Describe("internal doctor", func() {
+		g.It("should exit with status code 0 when all requirements are satisfied", func() {
+			out := execQuiet("internal doctor")
+			g.Assert(out.ExitCode).Equal(0)
+		})
+
+		g.It("should report the status of all requirements", func() {
+			out := execQuiet("internal doctor", "test/data/runtime_test_requirements.yaml")
+			g.Assert(out.ExitCode).Equal(requirementsExitCode)
+
+			status := make(map[string]string)
+			test.CaptureOutput(func() {
+				context := NewContext(CLI, io.Headless())
+				runtime, err := NewRuntime([]string{"--centry-file", "test/data/runtime_test_requirements.yaml"}, context)
+				test.AssertNoError(g, err)
+
+				doctorCmd := &DoctorCommand{Runtime: runtime, Log: context.log.GetLogger().WithFields(logrus.Fields{})}
+				for _, check := range doctorCmd.Run() {
+					s := "ok"
+					if check.Result.Err != nil {
+						s = check.Result.Err.Error()
+					}
+					status[fmt.Sprintf("%s (%s)", check.Result.Requirement, check.RequiredBy)] = s
+				}
+			})
+
+			g.Assert(status["bash (config)"]).Equal("ok")
+			g.Assert(status["bash >=3 (command requirestest)"]).Equal("ok")
+			g.Assert(status["bash >=3 (command requirestest ok)"]).Equal("ok")
+			g.Assert(status["centry-missing-tool (command requirestest missing)"]).Equal(`required tool "centry-missing-tool" was not found in PATH`)
+			test.AssertStringContains(g, status["bash <2 (command requirestest version)"], "does not satisfy the version constraint")
+		})
+	})
+
+	g.Describe("internal explain", func() {
+		explainManifestPath := "test/data/runtime_test_explain.yaml"
+
+		g.AfterEach(func() {
+			os.Unsetenv("CENTRY_TEST_LEVEL")
+		})
+
+		g.It("should explain option values without executing the command", func() {
+			os.Setenv("CENTRY_TEST_LEVEL", "env")
+			out := execQuiet("--centry-profile test internal explain fromenvtest", explainManifestPath)
+			test.AssertStringContains(g, out.Stdout, "ENVIRONMENT VARIABLE")
+			test.AssertStringContains(g, out.Stdout, "CENTRY_QUIET             true      flag                                  centry-quiet (set=Global)             not prefixed (internal)")
+			test.AssertStringContains(g, out.Stdout, "EXPLAIN_LEVEL            env       environment (name=CENTRY_TEST_LEVEL)  level (set=Global)                    prefixed (EXPLAIN_)")
+			test.AssertStringContains(g, out.Stdout, "EXPLAIN_NAME             profile   profile (name=test)                   name (set=fromenvtest)                prefixed (EXPLAIN_)")
+			g.Assert(strings.Contains(out.Stdout, "context=")).IsFalse("expected command not to be executed")
+		})
+
+		g.It("should show flag as source when set by flag", func() {
+			out := execQuiet("--level=flag internal explain fromenvtest --name=foo", explainManifestPath)
+			test.AssertStringContains(g, out.Stdout, "EXPLAIN_LEVEL            flag")
+			test.AssertStringContains(g, out.Stdout, "EXPLAIN_NAME             foo")
+		})
+
+		g.It("should exit with an error when no command matches", func() {
+			out := execQuiet("internal explain commandnotdefined", explainManifestPath)
+			g.Assert(out.ExitCode).Equal(1)
+		})
+
+		g.It("should exit with a usage error when a flag is not defined by the command", func() {
+			out := execQuiet("internal explain fromenvtest --undefined", explainManifestPath)
+			g.Assert(out.ExitCode).Equal(usageExitCode)
+		})
+
+		g.It("should redact values of secret options", func() {
+			out := execQuiet("internal explain fromenvtest --name=foo", explainManifestPath)
+			test.AssertStringContains(g, out.Stdout, "EXPLAIN_TOKEN            ********")
+			g.Assert(strings.Contains(out.Stdout, "s3cret")).IsFalse("expected secret value to be redacted")
+		})
+	})
+
+	g.Describe("secret options", func() {
+		explainManifestPath := "test/data/runtime_test_explain.yaml"
+
+		g.It("should redact values of secret options in the generated source logged", func() {
+			out := execCentry("--token=t0k'en fromenvtest --name=foo", false, explainManifestPath)
+			test.AssertStringContains(g, out.Stderr, "generated bash source")
+			test.AssertStringContains(g, out.Stderr, "export EXPLAIN_TOKEN='********'")
+			g.Assert(strings.Contains(out.Stderr, "t0k")).IsFalse("expected secret value to be redacted")
+			test.AssertStringContains(g, out.Stdout, "context=")
+		})
+
+		g.It("should pass the value of secret options to the command", func() {
+			out := execCentry("--token=t0ken fromenvtest --name=foo", true, explainManifestPath)
+			test.AssertStringContains(g, out.Stdout, "token=t0ken")
+		})
+
+		g.It("should redact default values of secret options in help", func() {
+			out := execQuiet("--help", explainManifestPath)
+			test.AssertStringContains(g, out.Stdout, "(default: ********)")
+			g.Assert(strings.Contains(out.Stdout, "s3cret")).IsFalse("expected secret value to be redacted")
+		})
+
+		g.It("should redact values of secret flags in arguments", func() {
+			flags := []cli.Flag{
+				&SecretOptionFlag{StringFlag: cli.StringFlag{Name: "token", Aliases: []string{"t"}}},
+				&cli.StringFlag{Name: "name"},
+			}
+			args := redactSecretArgs([]string{"fromenvtest", "--token=s3cret", "--name=foo"}, flags)
+			g.Assert(args).Equal([]string{"fromenvtest", "--token=********", "--name=foo"})
+		})
+
+		g.It("should redact values of secret flags passed as separate arguments", func() {
+			flags := []cli.Flag{
+				&SecretOptionFlag{StringFlag: cli.StringFlag{Name: "token", Aliases: []string{"t"}}},
+				&cli.StringFlag{Name: "name"},
+			}
+			args := redactSecretArgs([]string{"--token", "s3cret", "fromenvtest", "--name", "foo"}, flags)
+			g.Assert(args).Equal([]string{"--token", "********", "fromenvtest", "--name", "foo"})
+
+			args = redactSecretArgs([]string{"fromenvtest", "-t", "s3cret", "-t=s3cret", "--", "-t", "arg"}, flags)
+			g.Assert(args).Equal([]string{"fromenvtest", "-t", "********", "-t=********", "--", "-t", "arg"})
+		})
 	})
 
 	g.Describe("help", func() {
@@ -566,6 +1476,8 @@ OPTIONS:
 				out := execQuiet("", "test/data/runtime_test_display_internal_options.yaml")
 				expected := `OPTIONS:
    --centry-config-log-level value  Overrides the log level (default: "info")
+   --centry-no-cache                Disables the function cache (default: false)
+   --centry-profile value           Activates a profile defined by the manifest
    --centry-quiet                   Disables logging (default: false)`
 
 				test.AssertStringContains(g, out.Stdout, expected)
@@ -604,6 +1516,12 @@ func execWithLogging(source string, params ...string) *execResult {
 	return execCentry(source, false, manifestPath)
 }
 
+// assertUsageError asserts that the command exited with the usage exit code, logging the message as an error
+func assertUsageError(g *G, out *execResult, message string) {
+	g.Assert(out.ExitCode).Equal(usageExitCode, out.Source)
+	test.AssertStringContains(g, out.Stderr, fmt.Sprintf("level=error msg=%q", message))
+}
+
 func execCentry(source string, quiet bool, manifestPath string) *execResult {
 	var exitCode int
 	var runtimeErr error
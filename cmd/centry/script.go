@@ -38,10 +38,11 @@ func (sc *ScriptCommand) ToCLICommand() *cli.Command {
 	cmdKeys := sc.GetCommandInvocationPath()
 	cmdName := cmdKeys[len(cmdKeys)-1]
 	cmdHidden := sc.Command.Hidden || sc.Function.Hidden
-	return withCommandDefaults(&cli.Command{
+	command := withCommandDefaults(&cli.Command{
 		Name:      cmdName,
 		Usage:     sc.Command.Description,
 		UsageText: sc.Command.Help,
+		Category:  sc.Command.Category,
 		Hidden:    cmdHidden,
 		Action: func(c *cli.Context) error {
 			err := validateOptions(c, sc, cmdName)
@@ -57,23 +58,46 @@ func (sc *ScriptCommand) ToCLICommand() *cli.Command {
 		},
 		Flags: optionsSetToFlags(sc.Function.Options),
 	})
+	command.BashComplete = commandCompletion(command, sc.Function.Options)
+	return command
 }
 
 // Run builds the source and executes it
 func (sc *ScriptCommand) Run(c *cli.Context, args []string) int {
 	sc.Log.Debugf("executing command \"%v\"", sc.Function.Name)
 
+	checker := newRequirementChecker()
+	failed := false
+	for _, r := range commandRequirements(sc.Context.manifest, sc.Command, sc.Function) {
+		if result := checker.Check(r); result.Err != nil {
+			sc.Log.Error(result.Err)
+			failed = true
+		}
+	}
+	if failed {
+		return requirementsExitCode
+	}
+
+	env, err := manifestToEnvVars(sc.Context, sc.Command)
+	if err != nil {
+		sc.Log.Errorf("failed to resolve environment variables. %v", err)
+		return 1
+	}
+
 	var source []string
 	switch sc.Script.Language() {
 	case "bash":
-		source = generateBashSource(c, sc, args)
-		sc.Log.Debugf("generated bash source\n%s\n", source)
+		source = generateBashSource(c, sc, env, args, false)
+		if sc.Log.Logger.IsLevelEnabled(logrus.DebugLevel) {
+			// Generated again with the values of secret options redacted, only when it is logged
+			sc.Log.Debugf("generated bash source\n%s\n", generateBashSource(c, sc, env, args, true))
+		}
 	default:
 		sc.Log.Errorf("unsupported script language %s", sc.Script.Language())
 		return 1
 	}
 
-	err := sc.Script.Executable().Run(sc.Context.io, source)
+	err = sc.Script.Executable().Run(sc.Context.io, source)
 	if err != nil {
 		exitCode := 1
 
@@ -92,16 +116,53 @@ func (sc *ScriptCommand) Run(c *cli.Context, args []string) int {
 }
 
 func validateOptions(c *cli.Context, sc *ScriptCommand, cmdName string) error {
-	if err := validateOptionsSet(c, sc.GlobalOptions, cmdName, "global", sc.Log.WithField("option-valiation", "global")); err != nil {
+	basePath := sc.Context.manifest.BasePath
+	if err := validateOptionsSet(c, sc.GlobalOptions, cmdName, "global", basePath, sc.Log.WithField("option-valiation", "global")); err != nil {
 		return err
 	}
-	if err := validateOptionsSet(c, sc.Function.Options, cmdName, "command", sc.Log.WithField("option-valiation", "command")); err != nil {
+	if err := validateOptionsSet(c, sc.Function.Options, cmdName, "command", basePath, sc.Log.WithField("option-valiation", "command")); err != nil {
 		return err
 	}
 	return nil
 }
 
-func generateBashSource(c *cli.Context, sc *ScriptCommand, args []string) []string {
+// optionEnvVarToBashSource returns the bash source exporting the environment variable of an option, values of secret options are replaced when redact is true.
+// List and map options are also declared as arrays (<NAME>_ARRAY and <NAME>_MAP) as the values may contain newlines.
+func optionEnvVarToBashSource(v shell.EnvironmentVariable, redact bool) []string {
+	if redact && v.Secret {
+		v.Value = cmd.RedactedValue
+	}
+
+	source := []string{}
+	if v.Value != "" {
+		value := v.Value
+		if v.IsString() || v.IsList() || v.IsMap() {
+			value = quote(v.Value)
+		}
+		source = append(source, fmt.Sprintf("export %s=%s", v.Name, value))
+	}
+
+	switch {
+	case v.IsList():
+		values := make([]string, 0)
+		for _, val := range v.Values {
+			values = append(values, quote(val))
+		}
+		source = append(source, fmt.Sprintf("declare -a %s_ARRAY=(%s)", v.Name, strings.Join(values, " ")))
+	case v.IsMap():
+		values := make([]string, 0)
+		for _, val := range v.Values {
+			key, value, _ := cmd.ParseMapEntry(val)
+			values = append(values, fmt.Sprintf("[%s]=%s", quote(key), quote(value)))
+		}
+		source = append(source, fmt.Sprintf("declare -A %s_MAP=(%s)", v.Name, strings.Join(values, " ")))
+	}
+
+	return source
+}
+
+// generateBashSource returns the arguments executing the command in bash, values of secret options are replaced when redact is true (for logging)
+func generateBashSource(c *cli.Context, sc *ScriptCommand, env []shell.EnvironmentVariable, args []string, redact bool) []string {
 	source := []string{}
 	source = append(source, "#!/usr/bin/env bash")
 
@@ -111,34 +172,37 @@ func generateBashSource(c *cli.Context, sc *ScriptCommand, args []string) []stri
 
 	source = append(source, "")
 	source = append(source, "# Set centry metadata")
-	source = append(source, fmt.Sprintf("export %s='%s'", "CENTRY_SCRIPT_FUNCTION", sc.Function.Name))
-	source = append(source, fmt.Sprintf("export %s='%s'", "CENTRY_SCRIPT_PATH", sc.Script.RelativePath()))
-	source = append(source, fmt.Sprintf("export %s='%s'", "CENTRY_COMMAND_NAME", sc.Command.Name))
+	source = append(source, fmt.Sprintf("export %s=%s", "CENTRY_SCRIPT_FUNCTION", quote(sc.Function.Name)))
+	source = append(source, fmt.Sprintf("export %s=%s", "CENTRY_SCRIPT_PATH", quote(sc.Script.RelativePath())))
+	source = append(source, fmt.Sprintf("export %s=%s", "CENTRY_COMMAND_NAME", quote(sc.Command.Name)))
+	if sc.Context.profile != nil {
+		source = append(source, fmt.Sprintf("export %s=%s", "CENTRY_PROFILE", quote(sc.Context.profile.Name)))
+	}
+
+	source = append(source, "")
+	source = append(source, "# Set environment variables from manifest")
+	for _, v := range env {
+		source = append(source, fmt.Sprintf("export %s=%s", v.Name, quote(v.Value)))
+	}
+
+	source = append(source, "")
+	source = append(source, "# Set environment variables from profile")
+	for _, v := range profileToEnvVars(sc.Context) {
+		source = append(source, fmt.Sprintf("export %s=%s", v.Name, quote(v.Value)))
+	}
 
 	source = append(source, "")
 	source = append(source, "# Set environment variables from global options")
 	conf := sc.Context.manifest.Config
-	for _, v := range optionsSetToEnvVars(c, sc.GlobalOptions, conf.EnvironmentPrefix) {
-		if v.Value != "" {
-			value := v.Value
-			if v.IsString() {
-				value = fmt.Sprintf("'%s'", v.Value)
-			}
-			source = append(source, fmt.Sprintf("export %s=%s", v.Name, value))
-		}
+	for _, v := range optionsSetToEnvVars(c, sc.GlobalOptions, conf.EnvironmentPrefix, sc.Context.manifest.BasePath) {
+		source = append(source, optionEnvVarToBashSource(v, redact)...)
 	}
 
 	source = append(source, "")
 	source = append(source, "# Set environment variables from options defined by command")
 
-	for _, v := range optionsSetToEnvVars(c, sc.Function.Options, conf.EnvironmentPrefix) {
-		if v.Value != "" {
-			value := v.Value
-			if v.IsString() {
-				value = fmt.Sprintf("'%s'", v.Value)
-			}
-			source = append(source, fmt.Sprintf("export %s=%s", v.Name, value))
-		}
+	for _, v := range optionsSetToEnvVars(c, sc.Function.Options, conf.EnvironmentPrefix, sc.Context.manifest.BasePath) {
+		source = append(source, optionEnvVarToBashSource(v, redact)...)
 	}
 
 	source = append(source, "")
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+	"github.com/urfave/cli/v2"
+)
+
+// SecretOptionFlag is a string flag holding the value of a secret option
+type SecretOptionFlag struct {
+	cli.StringFlag
+}
+
+// redactSecretArgs replaces the values of secret flags in args (--<name>=<value>, --<name> <value> and the same forms for aliases)
+func redactSecretArgs(args []string, flags []cli.Flag) []string {
+	secrets := make(map[string]bool)
+	for _, f := range flags {
+		if _, ok := f.(*SecretOptionFlag); !ok {
+			continue
+		}
+		for _, name := range f.Names() {
+			secrets[name] = true
+		}
+	}
+
+	redacted := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			redacted = append(redacted, args[i:]...)
+			break
+		}
+
+		parts := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)
+		if !strings.HasPrefix(arg, "-") || !secrets[parts[0]] {
+			redacted = append(redacted, arg)
+			continue
+		}
+
+		if len(parts) == 2 {
+			redacted = append(redacted, strings.SplitN(arg, "=", 2)[0]+"="+cmd.RedactedValue)
+		} else if i+1 < len(args) {
+			redacted = append(redacted, arg, cmd.RedactedValue)
+			i++
+		} else {
+			redacted = append(redacted, arg)
+		}
+	}
+	return redacted
+}
@@ -107,6 +107,7 @@ func (sc *ServeCommand) executeHandler() func(w http.ResponseWriter, r *http.Req
 		// Build
 		io, buf := io.BufferedCombined()
 		context := NewContext(API, io)
+		context.lazy = true
 
 		context.commandEnabledFunc = func(cmd config.Command) bool {
 			serveAnnotation, _ := cmd.Annotation(config.CommandAnnotationAPINamespace, "serve")
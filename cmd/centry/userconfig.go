@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
+)
+
+func loadUserConfig(runtime *Runtime) error {
+	context := runtime.context
+
+	userConfig, err := config.LoadUserConfig(context.manifest.Config.Name)
+	if err != nil {
+		return err
+	}
+
+	if userConfig != nil {
+		context.userConfig = userConfig
+		runtime.events = append(runtime.events, fmt.Sprintf("loaded user config (path=%s)", userConfig.Path))
+	}
+
+	return nil
+}
+
+// applyUserConfigDefaults overrides the default values of options with the defaults of the user config
+func applyUserConfigDefaults(runtime *Runtime, options *cmd.OptionsSet) {
+	userConfig := runtime.context.userConfig
+	if userConfig == nil {
+		return
+	}
+
+	for _, o := range options.Sorted() {
+		value, ok := userConfig.Defaults[o.Name]
+		if !ok {
+			continue
+		}
+
+		err := options.SetDefault(o.Name, value)
+		if err != nil {
+			runtime.events = append(runtime.events, fmt.Sprintf("failed to apply user config default for option \"%s\" (path=%s), error: %v", o.Name, userConfig.Path, err))
+			continue
+		}
+
+		// A value provided by the user config satisfies a required option
+		o.Required = false
+		o.DefaultSource = fmt.Sprintf("user config (path=%s)", userConfig.Path)
+
+		runtime.events = append(runtime.events, fmt.Sprintf("applied user config default for option \"%s\" (path=%s set=%s)", o.Name, userConfig.Path, options.Name))
+	}
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
+	"github.com/kristofferahl/go-centry/internal/pkg/shell"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var syntaxErrorPattern = regexp.MustCompile(`: line (\d+): (.*)$`)
+
+// echoedSourcePattern matches the source line that bash echoes after a syntax error
+var echoedSourcePattern = regexp.MustCompile("^`.*'$")
+
+// ValidateCommand is a Command implementation that validates the manifest and all scripts
+type ValidateCommand struct {
+	Runtime *Runtime
+	Log     *logrus.Entry
+}
+
+// ToCLICommand returns a CLI command
+func (sc *ValidateCommand) ToCLICommand() *cli.Command {
+	return withCommandDefaults(&cli.Command{
+		Name:      "validate",
+		Usage:     "Validates the manifest file and scripts",
+		UsageText: "",
+		Hidden:    false,
+		Action: func(c *cli.Context) error {
+			problems := sc.Run()
+			for _, p := range problems {
+				fmt.Fprintln(c.App.Writer, p.String())
+			}
+
+			if len(problems) > 0 {
+				return cli.Exit(fmt.Sprintf("validation failed, found %d problem(s)", len(problems)), 1)
+			}
+
+			sc.Log.Info("no problems found")
+			return nil
+		},
+	})
+}
+
+type validationProblem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (p validationProblem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", p.File, p.Message)
+}
+
+// validatedOption holds what is needed to validate an option and where it was declared
+type validatedOption struct {
+	option *cmd.Option
+	file   string
+	line   int
+}
+
+type validator struct {
+	runtime     *Runtime
+	problems    []validationProblem
+	invocations map[string]string
+	global      []*validatedOption
+}
+
+// Run validates the manifest and scripts and returns the problems found
+func (sc *ValidateCommand) Run() []validationProblem {
+	sc.Log.Debugf("validating manifest and scripts")
+
+	v := &validator{
+		runtime:     sc.Runtime,
+		problems:    make([]validationProblem, 0),
+		invocations: make(map[string]string),
+		global:      make([]*validatedOption, 0),
+	}
+
+	v.validateManifest()
+
+	for _, c := range expandManifestCommands(sc.Runtime) {
+		v.validateScript(c, createScript(c, sc.Runtime.context))
+	}
+
+	return v.result()
+}
+
+func (v *validator) report(file string, line int, format string, a ...interface{}) {
+	v.problems = append(v.problems, validationProblem{
+		File:    file,
+		Line:    line,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// result returns the problems sorted by file and line, problems reported more than once (scripts shared by commands) are removed
+func (v *validator) result() []validationProblem {
+	seen := make(map[string]bool)
+	problems := make([]validationProblem, 0)
+	for _, p := range v.problems {
+		if !seen[p.String()] {
+			seen[p.String()] = true
+			problems = append(problems, p)
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		return problems[i].Line < problems[j].Line
+	})
+
+	return problems
+}
+
+func (v *validator) validateManifest() {
+	manifest := v.runtime.context.manifest
+	file := filepath.Base(manifest.Path)
+
+	for _, s := range manifest.Scripts {
+		script := &shell.BashScript{BasePath: manifest.BasePath, Path: s}
+		if filepath.IsAbs(s) {
+			script.BasePath = ""
+		}
+		if err := script.CheckSyntax(); err != nil {
+			v.reportSyntaxError(s, err)
+		}
+	}
+
+	for _, c := range manifest.Commands {
+		v.validateAnnotationKeys(file, 0, c.Annotations)
+		for _, o := range c.Options {
+			v.validateAnnotationKeys(file, 0, o.Annotations)
+			v.validateOption(file, 0, mapOptionToCmdOption(o))
+		}
+		for _, f := range c.Functions {
+			for _, o := range f.Options {
+				v.validateAnnotationKeys(file, 0, o.Annotations)
+				v.validateOption(file, 0, mapOptionToCmdOption(o))
+			}
+		}
+	}
+
+	for _, o := range manifest.Options {
+		v.validateAnnotationKeys(file, 0, o.Annotations)
+		v.validateOption(file, 0, mapOptionToCmdOption(o))
+	}
+
+	v.global = v.manifestOptions(manifest.Options)
+	v.validateOptions(v.global)
+}
+
+// reportSyntaxError reports the errors of a syntax check, leaving out the source lines echoed by bash
+func (v *validator) reportSyntaxError(file string, err error) {
+	reported := false
+	for _, l := range strings.Split(err.Error(), "\n") {
+		m := syntaxErrorPattern.FindStringSubmatch(l)
+		if m == nil || echoedSourcePattern.MatchString(m[2]) {
+			continue
+		}
+		var line int
+		fmt.Sscanf(m[1], "%d", &line)
+		v.report(file, line, "%s", m[2])
+		reported = true
+	}
+
+	if !reported {
+		v.report(file, 0, "%v", err)
+	}
+}
+
+// validateOption reports an option that would be rejected when added to an options set
+func (v *validator) validateOption(file string, line int, o *cmd.Option) {
+	if err := cmd.NewOptionsSet(o.Name).Add(o); err != nil {
+		v.report(file, line, "%v", err)
+	}
+}
+
+// manifestOptions returns options declared in the manifest as options to validate
+func (v *validator) manifestOptions(options []config.Option) []*validatedOption {
+	file := filepath.Base(v.runtime.context.manifest.Path)
+
+	validated := make([]*validatedOption, 0)
+	for _, o := range options {
+		validated = append(validated, &validatedOption{
+			option: mapOptionToCmdOption(o),
+			file:   file,
+		})
+	}
+	return validated
+}
+
+// annotatedOptions validates and returns the options declared by the annotations of a function
+func (v *validator) annotatedOptions(file string, fn string, annotations []*config.Annotation) []*validatedOption {
+	validated := make([]*validatedOption, 0)
+	for _, ao := range shell.AnnotatedOptions(fn, annotations) {
+		for _, err := range ao.Errors {
+			v.report(file, err.Line, "%v", err)
+		}
+
+		line := ao.Line
+		if l, ok := ao.Lines["envName"]; ok {
+			line = l
+		}
+		if l, ok := ao.Lines["short"]; ok {
+			line = l
+		}
+
+		v.validateOption(file, line, ao.Option)
+
+		validated = append(validated, &validatedOption{option: ao.Option, file: file, line: line})
+	}
+	return validated
+}
+
+// commandOptions merges the options of a function with the options declared for the command in the manifest (see mergeCommandOptions)
+func (v *validator) commandOptions(c config.Command, fn string, annotated []*validatedOption) []*validatedOption {
+	options := make([]*validatedOption, 0)
+	for _, set := range [][]*validatedOption{v.manifestOptions(c.Functions[fn].Options), annotated, v.manifestOptions(c.Options)} {
+		for _, o := range set {
+			if findValidatedOption(options, o.option.Name) == nil {
+				options = append(options, o)
+			}
+		}
+	}
+	return options
+}
+
+func (v *validator) validateAnnotationKeys(file string, line int, annotations map[string]string) {
+	for nk := range annotations {
+		parts := strings.SplitN(nk, "/", 2)
+		if len(parts) != 2 {
+			v.report(file, line, "invalid annotation \"%s\"", nk)
+			continue
+		}
+
+		keys, ok := config.AnnotationKeys(parts[0])
+		if !ok {
+			v.report(file, line, "unknown annotation namespace \"%s\"", parts[0])
+		} else if !keys[parts[1]] {
+			v.report(file, line, "unknown annotation key \"%s\" (namespace=%s)", parts[1], parts[0])
+		}
+	}
+}
+
+func (v *validator) validateScript(c config.Command, script shell.Script) {
+	file := script.RelativePath()
+
+	if err := script.CheckSyntax(); err != nil {
+		v.reportSyntaxError(file, err)
+		return
+	}
+
+	funcs, err := script.Functions()
+	if err != nil {
+		v.report(file, 0, "failed to source script. %v", err)
+		return
+	}
+
+	annotations, err := script.FunctionAnnotations()
+	if err != nil {
+		v.report(file, 0, "failed to read annotations. %v", err)
+		return
+	}
+
+	functions := make(map[string]bool)
+	for _, fn := range funcs {
+		functions[fn.Name] = true
+	}
+
+	for _, a := range annotations {
+		keys, ok := config.AnnotationKeys(a.Namespace)
+		if !ok {
+			v.report(file, a.Line, "unknown annotation namespace \"%s\"", a.Namespace)
+			continue
+		}
+		if !keys[a.Key] {
+			v.report(file, a.Line, "unknown annotation key \"%s\" (namespace=%s)", a.Key, a.Namespace)
+			continue
+		}
+
+		fn := a.NamespaceValues["cmd"]
+		if fn != "" && a.Key == config.CommandAnnotationAliasesKey && isFunctionNamespace(funcs, script.FunctionNamespace(fn)) {
+			continue
+		}
+		if fn != "" && !functions[fn] {
+			v.report(file, a.Line, "annotation refers to function \"%s\" that does not exist", fn)
+			continue
+		}
+
+		if a.Namespace == config.CommandAnnotationCmdNamespace && a.Key == config.CommandAnnotationRequiresKey {
+			if _, err := config.ParseRequirements(a.Value); err != nil {
+				v.report(file, a.Line, "%v", err)
+			}
+		}
+	}
+
+	for _, fn := range funcs {
+		if fn.Name != c.Name && !strings.HasPrefix(fn.Name, script.FunctionNamespace(c.Name)) {
+			continue
+		}
+
+		sc := &ScriptCommand{Command: c, Script: script, Function: *fn}
+		invocation := sc.GetCommandInvocation()
+		origin := fmt.Sprintf("%s (function=%s)", file, fn.Name)
+		if existing, ok := v.invocations[invocation]; ok && existing != origin {
+			v.report(file, 0, "command \"%s\" is also defined by %s", invocation, existing)
+		} else {
+			v.invocations[invocation] = origin
+		}
+
+		merged := v.commandOptions(c, fn.Name, v.annotatedOptions(file, fn.Name, annotations))
+		v.validateOptions(merged)
+		v.validateOptionsAgainstGlobal(merged)
+	}
+}
+
+// validateOptions reports duplicate short names and environment variable collisions within a set of options
+func (v *validator) validateOptions(options []*validatedOption) {
+	prefix := v.runtime.context.manifest.Config.EnvironmentPrefix
+
+	shorts := make(map[string]*validatedOption)
+	envNames := make(map[string]*validatedOption)
+	for _, o := range options {
+		for _, short := range optionShortNames(o.option) {
+			if existing, ok := shorts[short]; ok {
+				v.report(o.file, o.line, "duplicate short name \"%s\" (options=%s,%s)", short, existing.option.Name, o.option.Name)
+			} else {
+				shorts[short] = o
+			}
+		}
+
+		envName := optionEnvName(o.option, prefix)
+		if existing, ok := envNames[envName]; ok && !isSelectGroup(existing.option, o.option) {
+			v.report(o.file, o.line, "environment variable \"%s\" is used by multiple options (options=%s,%s)", envName, existing.option.Name, o.option.Name)
+		} else if !ok {
+			envNames[envName] = o
+		}
+	}
+}
+
+// validateOptionsAgainstGlobal reports command options that would override the environment variable of a differently named global option
+func (v *validator) validateOptionsAgainstGlobal(options []*validatedOption) {
+	prefix := v.runtime.context.manifest.Config.EnvironmentPrefix
+
+	for _, o := range options {
+		envName := optionEnvName(o.option, prefix)
+		for _, g := range v.global {
+			if g.option.Name == o.option.Name {
+				continue
+			}
+			if optionEnvName(g.option, prefix) == envName && !isSelectGroup(g.option, o.option) {
+				v.report(o.file, o.line, "environment variable \"%s\" of option \"%s\" collides with global option \"%s\"", envName, o.option.Name, g.option.Name)
+			}
+		}
+	}
+}
+
+// isFunctionNamespace returns true if any function is declared in the namespace (placeholder commands)
+func isFunctionNamespace(funcs []*shell.Function, namespace string) bool {
+	for _, fn := range funcs {
+		if strings.HasPrefix(fn.Name, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+func findValidatedOption(options []*validatedOption, name string) *validatedOption {
+	for _, o := range options {
+		if o.option.Name == name {
+			return o
+		}
+	}
+	return nil
+}
+
+func optionShortNames(o *cmd.Option) []string {
+	shorts := make([]string, 0)
+	if o.Short != "" {
+		shorts = append(shorts, o.Short)
+	}
+	for _, ov := range o.Values {
+		if ov.Short != "" {
+			shorts = append(shorts, ov.Short)
+		}
+	}
+	return shorts
+}
+
+// isSelectGroup returns true when both options are select options, sharing an environment variable by design
+func isSelectGroup(a, b *cmd.Option) bool {
+	return a.Type == cmd.SelectOption && b.Type == cmd.SelectOption
+}
@@ -4,6 +4,8 @@ go 1.19
 
 require (
 	github.com/AlecAivazis/survey/v2 v2.3.7-0.20221208154106-fa37277e6394
+	github.com/BurntSushi/toml v1.2.1
+	github.com/Masterminds/semver/v3 v3.2.1
 	github.com/franela/goblin v0.0.0-20200105215937-c9ffbefa60db
 	github.com/ghodss/yaml v1.0.0
 	github.com/gorilla/mux v1.7.3
@@ -12,6 +14,7 @@ require (
 	github.com/sirupsen/logrus v1.4.2
 	github.com/urfave/cli/v2 v2.3.0
 	gopkg.in/yaml.v2 v2.2.7
+	mvdan.cc/sh/v3 v3.7.0
 )
 
 require (
@@ -24,7 +27,7 @@ require (
 	github.com/mgutz/ansi v0.0.0-20170206155736-9520e82c474b // indirect
 	github.com/russross/blackfriday/v2 v2.0.1 // indirect
 	github.com/shurcooL/sanitized_anchor_name v1.0.0 // indirect
-	golang.org/x/sys v0.5.0 // indirect
-	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211 // indirect
+	golang.org/x/sys v0.8.0 // indirect
+	golang.org/x/term v0.8.0 // indirect
 	golang.org/x/text v0.4.0 // indirect
 )
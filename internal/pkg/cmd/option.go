@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // OptionsSet represents a set of flags that can be passed to the cli
@@ -25,12 +26,33 @@ const BoolOption OptionType = "bool"
 // IntegerOption defines an interger value option
 const IntegerOption OptionType = "integer"
 
+// FloatOption defines a float value option
+const FloatOption OptionType = "float"
+
+// DurationOption defines a duration value option (e.g. 5m, 1h30m)
+const DurationOption OptionType = "duration"
+
+// ListOption defines a repeatable string value option (e.g. --tag a --tag b)
+const ListOption OptionType = "list"
+
+// MapOption defines a repeatable key=value option (e.g. --label env=prod --label team=x)
+const MapOption OptionType = "map"
+
+// PathOption defines a file or directory path option, resolved to an absolute path
+const PathOption OptionType = "path"
+
+// EnumOption defines a single value option, restricted to one of the option values (e.g. --format=json)
+const EnumOption OptionType = "enum"
+
 // SelectOption defines a select value option
 const SelectOption OptionType = "select"
 
 // SelectOptionV2 defines a select value option
 const SelectOptionV2 OptionType = "select/v2"
 
+// RedactedValue is displayed in place of the values of secret options
+const RedactedValue string = "********"
+
 // StringToOptionType returns the OptionType matching the provided string
 func StringToOptionType(s string) OptionType {
 	s = strings.ToLower(s)
@@ -41,6 +63,18 @@ func StringToOptionType(s string) OptionType {
 		return BoolOption
 	case "integer":
 		return IntegerOption
+	case "float":
+		return FloatOption
+	case "duration":
+		return DurationOption
+	case "list":
+		return ListOption
+	case "map":
+		return MapOption
+	case "path":
+		return PathOption
+	case "enum":
+		return EnumOption
 	case "select":
 		return SelectOption
 	case "select/v2":
@@ -56,12 +90,25 @@ type Option struct {
 	Name        string
 	Short       string
 	EnvName     string
+	FromEnv     string
 	Description string
 	Required    bool
 	Hidden      bool
 	Internal    bool
+	Secret      bool
 	Values      []OptionValue
 	Default     interface{}
+
+	// MustExist, Kind and Extensions constrain the values of path options
+	MustExist  bool
+	Kind       string
+	Extensions []string
+
+	// Rules are enforced for the values of the option before a command is executed
+	Rules OptionRules
+
+	// DefaultSource describes where the default value was set from when overridden (profile, user config etc.)
+	DefaultSource string
 }
 
 type OptionValue struct {
@@ -77,6 +124,33 @@ func (ov OptionValue) ResolveValue() string {
 	return ov.Name
 }
 
+// HasValue returns true if the option has a value with the given name
+func (o *Option) HasValue(name string) bool {
+	for _, ov := range o.Values {
+		if ov.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// displayValue returns the value as it may be displayed, redacted for secret options
+func (o *Option) displayValue(value string) string {
+	if o.Secret {
+		return RedactedValue
+	}
+	return value
+}
+
+// ValueNames returns the names of the option values
+func (o *Option) ValueNames() []string {
+	names := make([]string, 0, len(o.Values))
+	for _, ov := range o.Values {
+		names = append(names, ov.Name)
+	}
+	return names
+}
+
 // Validate returns true if the option is considered valid
 func (o *Option) Validate() error {
 	if o.Name == "" {
@@ -93,6 +167,22 @@ func (o *Option) Validate() error {
 		}
 	}
 
+	if o.Type == EnumOption && len(o.Values) == 0 {
+		return fmt.Errorf("missing option values for enum option \"%s\"", o.Name)
+	}
+
+	if err := o.Rules.validate(o.Name, o.Type); err != nil {
+		return err
+	}
+
+	if o.Secret && o.Type != StringOption {
+		return fmt.Errorf("secret is only supported by string options (option=%s type=%s)", o.Name, o.Type)
+	}
+
+	if o.Kind != "" && o.Kind != PathKindFile && o.Kind != PathKindDir {
+		return fmt.Errorf("invalid path kind \"%s\" (option=%s)", o.Kind, o.Name)
+	}
+
 	return nil
 }
 
@@ -163,6 +253,31 @@ func (s *OptionsSet) Add(option *Option) error {
 	return nil
 }
 
+// SetDefault overrides the default value of an option in the set
+func (s *OptionsSet) SetDefault(name string, value interface{}) error {
+	option, ok := s.items[name]
+	if !ok {
+		return fmt.Errorf("an option with the name \"%s\" has not been added", name)
+	}
+
+	previous := option.Default
+	option.Default = value
+
+	err := convertDefaultValueToCorrectType(option)
+	if err != nil {
+		option.Default = previous
+		return err
+	}
+
+	return nil
+}
+
+// Has returns true if an option with the given name has been added to the set
+func (s *OptionsSet) Has(name string) bool {
+	_, ok := s.items[name]
+	return ok
+}
+
 // Sorted returns the options sorted by it's key
 func (s *OptionsSet) Sorted() []*Option {
 	keys := make([]string, 0, len(s.items))
@@ -186,11 +301,22 @@ func convertDefaultValueToCorrectType(option *Option) error {
 	switch option.Type {
 	case SelectOption:
 		def = false
-	case SelectOptionV2:
-		def = false
+	case SelectOptionV2, EnumOption:
+		def = ""
+		switch option.Default.(type) {
+		case string:
+			if option.Default != "" {
+				if !option.HasValue(option.Default.(string)) {
+					return fmt.Errorf("default value \"%s\" is not a value of option \"%s\"", option.Default, option.Name)
+				}
+				def = option.Default
+			}
+		}
 	case IntegerOption:
 		def = 0
 		switch option.Default.(type) {
+		case int:
+			def = option.Default
 		case string:
 			if option.Default != "" {
 				val, err := strconv.Atoi(option.Default.(string))
@@ -200,9 +326,68 @@ func convertDefaultValueToCorrectType(option *Option) error {
 				def = val
 			}
 		}
+	case FloatOption:
+		def = float64(0)
+		switch option.Default.(type) {
+		case float64:
+			def = option.Default
+		case int:
+			def = float64(option.Default.(int))
+		case string:
+			if option.Default != "" {
+				val, err := strconv.ParseFloat(option.Default.(string), 64)
+				if err != nil {
+					return err
+				}
+				def = val
+			}
+		}
+	case DurationOption:
+		def = time.Duration(0)
+		switch option.Default.(type) {
+		case time.Duration:
+			def = option.Default
+		case string:
+			if option.Default != "" {
+				val, err := time.ParseDuration(option.Default.(string))
+				if err != nil {
+					return err
+				}
+				def = val
+			}
+		}
+	case ListOption, MapOption:
+		def = []string{}
+		switch option.Default.(type) {
+		case []string:
+			def = option.Default
+		case string:
+			if option.Default != "" {
+				def = strings.Split(option.Default.(string), ",")
+			}
+		}
+		if option.Type == MapOption {
+			for _, e := range def.([]string) {
+				if _, _, err := ParseMapEntry(e); err != nil {
+					return err
+				}
+			}
+		}
 	case BoolOption:
 		def = false
-	case StringOption:
+		switch option.Default.(type) {
+		case bool:
+			def = option.Default
+		case string:
+			if option.Default != "" {
+				val, err := strconv.ParseBool(option.Default.(string))
+				if err != nil {
+					return err
+				}
+				def = val
+			}
+		}
+	case StringOption, PathOption:
 		def = option.Default
 	default:
 		return fmt.Errorf("default value conversion not registered for type \"%s\"", option.Type)
@@ -213,6 +398,15 @@ func convertDefaultValueToCorrectType(option *Option) error {
 	return nil
 }
 
+// ParseMapEntry splits a map option value (key=value) into it's key and value
+func ParseMapEntry(s string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("invalid map value \"%s\" (expected key=value)", s)
+	}
+	return parts[0], parts[1], nil
+}
+
 func contains[T comparable](s []T, e T) bool {
 	for _, v := range s {
 		if v == e {
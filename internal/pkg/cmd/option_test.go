@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"io/ioutil"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -92,6 +95,112 @@ func TestMain(t *testing.T) {
 				g.Assert(err2 != nil).IsTrue("expected an error")
 				g.Assert(err2.Error()).Equal("an option value with the name \"Opt2\" has already been added")
 			})
+
+			g.It("should convert bool default value", func() {
+				os := NewOptionsSet("Name")
+				os.Add(&Option{Name: "Option", Type: BoolOption, Default: "true"})
+				g.Assert(os.Sorted()[0].Default).Equal(true)
+			})
+
+			g.It("should convert float default value", func() {
+				os := NewOptionsSet("Name")
+				os.Add(&Option{Name: "Option", Type: FloatOption, Default: "0.75"})
+				g.Assert(os.Sorted()[0].Default).Equal(0.75)
+			})
+
+			g.It("should return error when float default value is invalid", func() {
+				os := NewOptionsSet("Name")
+				err := os.Add(&Option{Name: "Option", Type: FloatOption, Default: "three quarters"})
+				g.Assert(len(os.Sorted())).Equal(0)
+				g.Assert(err != nil).IsTrue("expected an error")
+			})
+
+			g.It("should convert duration default value", func() {
+				os := NewOptionsSet("Name")
+				os.Add(&Option{Name: "Option", Type: DurationOption, Default: "1h30m"})
+				g.Assert(os.Sorted()[0].Default).Equal(90 * time.Minute)
+			})
+
+			g.It("should return error when duration default value is invalid", func() {
+				os := NewOptionsSet("Name")
+				err := os.Add(&Option{Name: "Option", Type: DurationOption, Default: "5 minutes"})
+				g.Assert(len(os.Sorted())).Equal(0)
+				g.Assert(err != nil).IsTrue("expected an error")
+			})
+
+			g.It("should convert list default value", func() {
+				os := NewOptionsSet("Name")
+				os.Add(&Option{Name: "Option", Type: ListOption, Default: "a,b"})
+				g.Assert(os.Sorted()[0].Default).Equal([]string{"a", "b"})
+			})
+
+			g.It("should convert map default value", func() {
+				os := NewOptionsSet("Name")
+				os.Add(&Option{Name: "Option", Type: MapOption, Default: "env=prod,team=x"})
+				g.Assert(os.Sorted()[0].Default).Equal([]string{"env=prod", "team=x"})
+			})
+
+			g.It("should return error when map default value is invalid", func() {
+				os := NewOptionsSet("Name")
+				err := os.Add(&Option{Name: "Option", Type: MapOption, Default: "env"})
+				g.Assert(len(os.Sorted())).Equal(0)
+				g.Assert(err != nil).IsTrue("expected an error")
+			})
+
+			g.It("should keep select option v2 default value", func() {
+				os := NewOptionsSet("Name")
+				err := os.Add(&Option{Name: "Foo", Type: SelectOptionV2, Values: []OptionValue{{Name: "Opt1"}, {Name: "Opt2"}}, Default: "Opt2"})
+				g.Assert(err).Equal(nil)
+				g.Assert(os.Sorted()[0].Default).Equal("Opt2")
+			})
+
+			g.It("should return error when select option v2 default value is not a value", func() {
+				os := NewOptionsSet("Name")
+				err := os.Add(&Option{Name: "Foo", Type: SelectOptionV2, Values: []OptionValue{{Name: "Opt1"}}, Default: "Opt2"})
+				g.Assert(len(os.Sorted())).Equal(0)
+				g.Assert(err.Error()).Equal("default value \"Opt2\" is not a value of option \"Foo\"")
+			})
+		})
+
+		g.Describe("Has", func() {
+			g.It("should return true when option has been added", func() {
+				os := NewOptionsSet("Name")
+				os.Add(&Option{Name: "Option", Type: StringOption})
+				g.Assert(os.Has("Option")).IsTrue()
+				g.Assert(os.Has("Other")).IsFalse()
+			})
+		})
+
+		g.Describe("SetDefault", func() {
+			g.It("should override the default value", func() {
+				os := NewOptionsSet("Name")
+				os.Add(&Option{Name: "Option", Type: IntegerOption, Default: "1"})
+				err := os.SetDefault("Option", "2")
+				g.Assert(err).Equal(nil)
+				g.Assert(os.Sorted()[0].Default).Equal(2)
+			})
+
+			g.It("should keep the default value when conversion fails", func() {
+				os := NewOptionsSet("Name")
+				os.Add(&Option{Name: "Option", Type: IntegerOption, Default: "1"})
+				err := os.SetDefault("Option", "two")
+				g.Assert(err != nil).IsTrue("expected an error")
+				g.Assert(os.Sorted()[0].Default).Equal(1)
+			})
+
+			g.It("should keep integer default value", func() {
+				os := NewOptionsSet("Name")
+				os.Add(&Option{Name: "Option", Type: IntegerOption, Default: "1"})
+				err := os.SetDefault("Option", 2)
+				g.Assert(err).Equal(nil)
+				g.Assert(os.Sorted()[0].Default).Equal(2)
+			})
+
+			g.It("should return error when option does not exist", func() {
+				os := NewOptionsSet("Name")
+				err := os.SetDefault("Option", "value")
+				g.Assert(err.Error()).Equal("an option with the name \"Option\" has not been added")
+			})
 		})
 	})
 
@@ -118,6 +227,36 @@ func TestMain(t *testing.T) {
 			g.Assert(StringToOptionType("INTEGER")).Equal(IntegerOption)
 		})
 
+		g.It("should return FloatOption", func() {
+			g.Assert(StringToOptionType("float")).Equal(FloatOption)
+			g.Assert(StringToOptionType("Float")).Equal(FloatOption)
+			g.Assert(StringToOptionType("FLOAT")).Equal(FloatOption)
+		})
+
+		g.It("should return DurationOption", func() {
+			g.Assert(StringToOptionType("duration")).Equal(DurationOption)
+			g.Assert(StringToOptionType("Duration")).Equal(DurationOption)
+			g.Assert(StringToOptionType("DURATION")).Equal(DurationOption)
+		})
+
+		g.It("should return ListOption", func() {
+			g.Assert(StringToOptionType("list")).Equal(ListOption)
+			g.Assert(StringToOptionType("List")).Equal(ListOption)
+			g.Assert(StringToOptionType("LIST")).Equal(ListOption)
+		})
+
+		g.It("should return MapOption", func() {
+			g.Assert(StringToOptionType("map")).Equal(MapOption)
+			g.Assert(StringToOptionType("Map")).Equal(MapOption)
+			g.Assert(StringToOptionType("MAP")).Equal(MapOption)
+		})
+
+		g.It("should return PathOption", func() {
+			g.Assert(StringToOptionType("path")).Equal(PathOption)
+			g.Assert(StringToOptionType("Path")).Equal(PathOption)
+			g.Assert(StringToOptionType("PATH")).Equal(PathOption)
+		})
+
 		g.It("should return SelectOption", func() {
 			g.Assert(StringToOptionType("select")).Equal(SelectOption)
 			g.Assert(StringToOptionType("Select")).Equal(SelectOption)
@@ -130,4 +269,106 @@ func TestMain(t *testing.T) {
 			g.Assert(StringToOptionType("SELECT/V2")).Equal(SelectOptionV2)
 		})
 	})
+
+	g.Describe("ValidatePath", func() {
+		dir, _ := ioutil.TempDir("", "centry-path")
+		file := filepath.Join(dir, "values.yaml")
+		ioutil.WriteFile(file, []byte{}, 0644)
+
+		g.After(func() {
+			os.RemoveAll(dir)
+		})
+
+		g.It("should pass for existing file", func() {
+			o := &Option{Name: "Option", Type: PathOption, MustExist: true, Kind: PathKindFile, Extensions: []string{"yml", ".yaml"}}
+			g.Assert(o.ValidatePath(file)).Equal(nil)
+		})
+
+		g.It("should pass for missing path when it is not required to exist", func() {
+			o := &Option{Name: "Option", Type: PathOption, Kind: PathKindFile}
+			g.Assert(o.ValidatePath(filepath.Join(dir, "missing.yaml"))).Equal(nil)
+		})
+
+		g.It("should return error for missing path when it must exist", func() {
+			o := &Option{Name: "Option", Type: PathOption, MustExist: true}
+			g.Assert(o.ValidatePath(filepath.Join(dir, "missing.yaml")) != nil).IsTrue("expected an error")
+		})
+
+		g.It("should return error when kind does not match", func() {
+			g.Assert((&Option{Name: "Option", Type: PathOption, Kind: PathKindDir}).ValidatePath(file) != nil).IsTrue("expected an error")
+			g.Assert((&Option{Name: "Option", Type: PathOption, Kind: PathKindFile}).ValidatePath(dir) != nil).IsTrue("expected an error")
+		})
+
+		g.It("should return error when extension does not match", func() {
+			o := &Option{Name: "Option", Type: PathOption, Extensions: []string{"json"}}
+			g.Assert(o.ValidatePath(file) != nil).IsTrue("expected an error")
+		})
+
+		g.It("should return error for invalid kind", func() {
+			os := NewOptionsSet("Name")
+			err := os.Add(&Option{Name: "Option", Type: PathOption, Kind: "socket"})
+			g.Assert(err != nil).IsTrue("expected an error")
+		})
+	})
+
+	g.Describe("ValidateValue", func() {
+		min, max := 1, 65535
+		minLength, maxLength := 2, 8
+
+		g.It("should return error when value is outside min and max", func() {
+			o := &Option{Name: "port", Type: IntegerOption, Rules: OptionRules{Min: &min, Max: &max}}
+			g.Assert(o.ValidateValue("8080")).Equal(nil)
+			g.Assert(o.ValidateValue("0").Error()).Equal("value 0 is less than the minimum of 1")
+			g.Assert(o.ValidateValue("65536").Error()).Equal("value 65536 is greater than the maximum of 65535")
+		})
+
+		g.It("should return error when value does not match pattern", func() {
+			o := &Option{Name: "name", Type: StringOption, Rules: OptionRules{Pattern: "^[a-z]+$"}}
+			g.Assert(o.ValidateValue("web")).Equal(nil)
+			g.Assert(o.ValidateValue("Web").Error()).Equal("value \"Web\" does not match pattern \"^[a-z]+$\"")
+		})
+
+		g.It("should return error when length of value is out of bounds", func() {
+			o := &Option{Name: "name", Type: StringOption, Rules: OptionRules{MinLength: &minLength, MaxLength: &maxLength}}
+			g.Assert(o.ValidateValue("web")).Equal(nil)
+			g.Assert(o.ValidateValue("w").Error()).Equal("value \"w\" is shorter than the minimum length of 2")
+			g.Assert(o.ValidateValue("webserver").Error()).Equal("value \"webserver\" is longer than the maximum length of 8")
+		})
+
+		g.It("should return error when value is not one of the allowed values", func() {
+			o := &Option{Name: "env", Type: StringOption, Rules: OptionRules{OneOf: []string{"dev", "prod"}}}
+			g.Assert(o.ValidateValue("dev")).Equal(nil)
+			g.Assert(o.ValidateValue("test").Error()).Equal("value \"test\" is not one of \" dev | prod \"")
+		})
+
+		g.It("should return error when value is not a value of an enum option", func() {
+			o := &Option{Name: "format", Type: EnumOption, Values: []OptionValue{{Name: "json"}, {Name: "yaml"}}}
+			g.Assert(o.ValidateValue("json")).Equal(nil)
+			g.Assert(o.ValidateValue("xml").Error()).Equal("value \"xml\" is not one of \" json | yaml \"")
+		})
+
+		g.It("should return error when value of a map option is not key=value", func() {
+			o := &Option{Name: "label", Type: MapOption}
+			g.Assert(o.ValidateValue("env=prod")).Equal(nil)
+			g.Assert(o.ValidateValue("env").Error()).Equal("invalid map value \"env\" (expected key=value)")
+		})
+
+		g.It("should redact values of secret options in errors", func() {
+			o := &Option{Name: "token", Type: StringOption, Secret: true, Rules: OptionRules{MinLength: &minLength, Pattern: "^[a-z]+$"}}
+			g.Assert(o.ValidateValue("x").Error()).Equal("value \"********\" is shorter than the minimum length of 2")
+			g.Assert(o.ValidateValue("X1").Error()).Equal("value \"********\" does not match pattern \"^[a-z]+$\"")
+		})
+
+		g.It("should return error when adding secret option that is not a string option", func() {
+			os := NewOptionsSet("Name")
+			err := os.Add(&Option{Name: "pin", Type: IntegerOption, Secret: true})
+			g.Assert(err.Error()).Equal("secret is only supported by string options (option=pin type=integer)")
+		})
+
+		g.It("should return error when adding option with invalid rules", func() {
+			os := NewOptionsSet("Name")
+			g.Assert(os.Add(&Option{Name: "name", Type: StringOption, Rules: OptionRules{Pattern: "["}}) != nil).IsTrue("expected an error")
+			g.Assert(os.Add(&Option{Name: "name", Type: StringOption, Rules: OptionRules{Min: &min}}) != nil).IsTrue("expected an error")
+		})
+	})
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathKindFile defines a path option that must refer to a file
+const PathKindFile string = "file"
+
+// PathKindDir defines a path option that must refer to a directory
+const PathKindDir string = "dir"
+
+// ResolvePath returns the absolute path of a path option value, relative paths are resolved against base (the working directory when base is empty)
+func ResolvePath(value string, base string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if base != "" && !filepath.IsAbs(value) {
+		value = filepath.Join(base, value)
+	}
+	return filepath.Abs(value)
+}
+
+// ValidatePath returns an error if the path does not satisfy the existence, kind and extension constraints of the option
+func (o *Option) ValidatePath(path string) error {
+	if len(o.Extensions) > 0 {
+		ext := strings.TrimPrefix(filepath.Ext(path), ".")
+		found := false
+		for _, e := range o.Extensions {
+			if strings.EqualFold(strings.TrimPrefix(e, "."), ext) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("path \"%s\" must have one of the extensions %s", path, strings.Join(o.Extensions, ", "))
+		}
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if o.MustExist {
+				return fmt.Errorf("path \"%s\" does not exist", path)
+			}
+			return nil
+		}
+		return err
+	}
+
+	switch o.Kind {
+	case PathKindFile:
+		if fi.IsDir() {
+			return fmt.Errorf("path \"%s\" is not a file", path)
+		}
+	case PathKindDir:
+		if !fi.IsDir() {
+			return fmt.Errorf("path \"%s\" is not a directory", path)
+		}
+	}
+
+	return nil
+}
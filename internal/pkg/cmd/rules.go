@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OptionRules defines declarative validation rules for the values of an option
+type OptionRules struct {
+	Pattern   string   `json:"pattern,omitempty"`
+	Min       *int     `json:"min,omitempty"`
+	Max       *int     `json:"max,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	OneOf     []string `json:"oneOf,omitempty"`
+}
+
+// IsEmpty returns true if no rules are set
+func (r OptionRules) IsEmpty() bool {
+	return r.Pattern == "" && r.Min == nil && r.Max == nil && r.MinLength == nil && r.MaxLength == nil && len(r.OneOf) == 0
+}
+
+// validate returns an error if the rules can not be applied to an option of the given type
+func (r OptionRules) validate(name string, t OptionType) error {
+	if r.Pattern != "" {
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern for option \"%s\" (pattern=%s). %v", name, r.Pattern, err)
+		}
+	}
+
+	if (r.Min != nil || r.Max != nil) && t != IntegerOption {
+		return fmt.Errorf("min and max are only supported by integer options (option=%s type=%s)", name, t)
+	}
+
+	return nil
+}
+
+// ValidateValue returns an error if a value of the option (an item of list and map options) is not allowed by the option
+func (o *Option) ValidateValue(value string) error {
+	switch o.Type {
+	case EnumOption:
+		if !o.HasValue(value) {
+			return fmt.Errorf("value \"%s\" is not one of \" %s \"", value, strings.Join(o.ValueNames(), " | "))
+		}
+	case MapOption:
+		if _, _, err := ParseMapEntry(value); err != nil {
+			return err
+		}
+	}
+
+	r := o.Rules
+
+	if r.Pattern != "" {
+		matched, err := regexp.MatchString(r.Pattern, value)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			return fmt.Errorf("value \"%s\" does not match pattern \"%s\"", o.displayValue(value), r.Pattern)
+		}
+	}
+
+	if r.Min != nil || r.Max != nil {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("value \"%s\" is not an integer", value)
+		}
+		if r.Min != nil && i < *r.Min {
+			return fmt.Errorf("value %d is less than the minimum of %d", i, *r.Min)
+		}
+		if r.Max != nil && i > *r.Max {
+			return fmt.Errorf("value %d is greater than the maximum of %d", i, *r.Max)
+		}
+	}
+
+	if r.MinLength != nil && len(value) < *r.MinLength {
+		return fmt.Errorf("value \"%s\" is shorter than the minimum length of %d", o.displayValue(value), *r.MinLength)
+	}
+
+	if r.MaxLength != nil && len(value) > *r.MaxLength {
+		return fmt.Errorf("value \"%s\" is longer than the maximum length of %d", o.displayValue(value), *r.MaxLength)
+	}
+
+	if len(r.OneOf) > 0 && !contains(r.OneOf, value) {
+		return fmt.Errorf("value \"%s\" is not one of \" %s \"", o.displayValue(value), strings.Join(r.OneOf, " | "))
+	}
+
+	return nil
+}
+
+// HasDefault returns true if the option has a default value other than the zero value of it's type
+func (o *Option) HasDefault() bool {
+	switch d := o.Default.(type) {
+	case nil:
+		return false
+	case string:
+		return d != ""
+	case int:
+		return d != 0
+	case float64:
+		return d != 0
+	case time.Duration:
+		return d != 0
+	case bool:
+		return d
+	case []string:
+		return len(d) > 0
+	}
+	return true
+}
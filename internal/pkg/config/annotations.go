@@ -15,15 +15,55 @@ const CommandAnnotationCmdNamespace string = "centry.cmd"
 // CommandAnnotationCmdOptionNamespace defines an annotation namespace
 const CommandAnnotationCmdOptionNamespace string = "centry.cmd.option"
 
+// CommandAnnotationNameKey defines the annotation key used to name a command discovered by a glob pattern
+const CommandAnnotationNameKey string = "name"
+
+// CommandAnnotationAliasesKey defines the annotation key used for command aliases
+const CommandAnnotationAliasesKey string = "aliases"
+
 // CommandAnnotationAPINamespace defines an annotation namespace
 const CommandAnnotationAPINamespace string = "centry.api"
 
+// CommandAnnotationRequiresKey defines the annotation key used for external tools required by a command
+const CommandAnnotationRequiresKey string = "requires"
+
+// CommandAnnotationCmdKeys defines the keys supported by the centry.cmd namespace
+var CommandAnnotationCmdKeys = []string{"description", "help", "category", "hidden", CommandAnnotationNameKey, CommandAnnotationAliasesKey, CommandAnnotationRequiresKey}
+
+// CommandAnnotationCmdOptionKeys defines the keys supported by the centry.cmd.option namespace
+var CommandAnnotationCmdOptionKeys = []string{"type", "short", "envName", "fromEnv", "default", "required", "secret", "description", "hidden", "values", "mustExist", "kind", "extensions", "pattern", "min", "max", "minLength", "maxLength", "oneOf"}
+
+// CommandAnnotationAPIKeys defines the keys supported by the centry.api namespace
+var CommandAnnotationAPIKeys = []string{"serve"}
+
 // Annotation defines an annotation
 type Annotation struct {
 	Namespace       string
 	NamespaceValues map[string]string
 	Key             string
 	Value           string
+	Line            int
+}
+
+// AnnotationKeys returns the keys supported by a namespace, false when the namespace is unknown
+func AnnotationKeys(namespace string) (map[string]bool, bool) {
+	var keys []string
+	switch namespace {
+	case CommandAnnotationCmdNamespace:
+		keys = CommandAnnotationCmdKeys
+	case CommandAnnotationCmdOptionNamespace:
+		keys = CommandAnnotationCmdOptionKeys
+	case CommandAnnotationAPINamespace:
+		keys = CommandAnnotationAPIKeys
+	default:
+		return nil, false
+	}
+
+	supported := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		supported[k] = true
+	}
+	return supported, true
 }
 
 // AnnotationNamespaceKey creates an annotation namespace/key string
@@ -61,6 +101,18 @@ func ParseAnnotation(text string) (*Annotation, error) {
 	}, nil
 }
 
+// ParseAnnotationList parses a comma separated annotation value into a list of values
+func ParseAnnotationList(value string) []string {
+	values := make([]string, 0)
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 func extractNamespaceValues(namespace string) (params map[string]string) {
 	var compRegEx = regexp.MustCompile("\\.(\\w+)\\[([0-9A-Za-z_:-]+)\\]")
 	match := compRegEx.FindAllStringSubmatch(namespace, -1)
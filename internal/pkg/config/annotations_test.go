@@ -122,5 +122,12 @@ func TestAnnotations(t *testing.T) {
 				g.Assert(annotation.Value).Equal("bar")
 			})
 		})
+
+		g.Describe("ParseAnnotationList", func() {
+			g.It("returns trimmed values and skips empty values", func() {
+				values := ParseAnnotationList(" rm, del,,d ")
+				g.Assert(values).Equal([]string{"rm", "del", "d"})
+			})
+		})
 	})
 }
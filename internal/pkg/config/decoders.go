@@ -0,0 +1,63 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ghodss/yaml"
+)
+
+// manifestDecoder converts the content of a manifest file to json
+type manifestDecoder func(bs []byte) ([]byte, error)
+
+var manifestDecoders = map[string]manifestDecoder{
+	".yaml": decodeYaml,
+	".yml":  decodeYaml,
+	".json": decodeJSON,
+	".toml": decodeToml,
+}
+
+func manifestDecoderFor(path string) (manifestDecoder, error) {
+	decoder, ok := manifestDecoders[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("manifest file must have a %s extension (path=%s)", supportedManifestExtensions(), path)
+	}
+	return decoder, nil
+}
+
+func supportedManifestExtensions() string {
+	extensions := make([]string, 0, len(manifestDecoders))
+	for ext := range manifestDecoders {
+		extensions = append(extensions, strings.TrimPrefix(ext, "."))
+	}
+	sort.Strings(extensions)
+	return strings.Join(extensions[:len(extensions)-1], ", ") + " or " + extensions[len(extensions)-1]
+}
+
+func decodeYaml(bs []byte) ([]byte, error) {
+	jbs, err := yaml.YAMLToJSON(bs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest yaml. %v", err)
+	}
+	return jbs, nil
+}
+
+func decodeJSON(bs []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest json. %v", err)
+	}
+	return json.Marshal(v)
+}
+
+func decodeToml(bs []byte) ([]byte, error) {
+	v := make(map[string]interface{})
+	if err := toml.Unmarshal(bs, &v); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest toml. %v", err)
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// EnvFile defines the structure of a reference to a dotenv file
+type EnvFile struct {
+	Path     string `yaml:"path,omitempty"`
+	Optional bool   `yaml:"optional,omitempty"`
+}
+
+// ReadEnvFile reads environment variables from a dotenv file.
+// Supports blank lines, comments, an optional export prefix as well as single and double quoted values.
+func ReadEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	env := make(map[string]string)
+
+	line := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line++
+
+		l := strings.TrimSpace(scanner.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		l = strings.TrimSpace(strings.TrimPrefix(l, "export "))
+
+		kv := strings.SplitN(l, "=", 2)
+		name := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || !envNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid line in env file (path=%s line=%d)", path, line)
+		}
+
+		value, err := parseEnvValue(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in env file (path=%s line=%d). %v", path, line, err)
+		}
+
+		env[name] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func parseEnvValue(v string) (string, error) {
+	if v == "" {
+		return "", nil
+	}
+
+	switch v[0] {
+	case '\'':
+		end := strings.Index(v[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("missing closing quote")
+		}
+		return v[1 : end+1], nil
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(v); i++ {
+			switch {
+			case v[i] == '"':
+				return b.String(), nil
+			case v[i] == '\\' && i+1 < len(v):
+				i++
+				switch v[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(v[i])
+				}
+			default:
+				b.WriteByte(v[i])
+			}
+		}
+		return "", fmt.Errorf("missing closing quote")
+	}
+
+	// Unquoted values end at an inline comment
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = v[:i]
+	}
+
+	return strings.TrimSpace(v), nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestDotenv(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("ReadEnvFile", func() {
+		g.It("returns environment variables", func() {
+			env, err := ReadEnvFile("test/data/env/parse.env")
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(env["UNQUOTED"]).Equal("value")
+			g.Assert(env["EXPORTED"]).Equal("exported")
+			g.Assert(env["SINGLE"]).Equal("single # not a comment")
+			g.Assert(env["DOUBLE"]).Equal("line1\nline2 \"quoted\"")
+			g.Assert(env["EMPTY"]).Equal("")
+			g.Assert(env["SPACED"]).Equal("spaced value")
+			g.Assert(len(env)).Equal(6)
+		})
+
+		g.It("returns error for invalid line", func() {
+			_, err := ReadEnvFile("test/data/env/invalid.env")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("invalid line in env file (path=test/data/env/invalid.env line=2)")
+		})
+
+		g.It("returns error when file does not exist", func() {
+			_, err := ReadEnvFile("test/data/env/missing.env")
+			g.Assert(err != nil).IsTrue("expected error")
+		})
+	})
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+)
+
+// includeResolver merges included manifest files into the root manifest
+type includeResolver struct {
+	root     *Manifest
+	visited  map[string]bool
+	commands map[string]string
+	options  map[string]string
+	scripts  map[string]bool
+}
+
+func resolveIncludes(m *Manifest) error {
+	r := &includeResolver{
+		root:     m,
+		visited:  map[string]bool{m.Path: true},
+		commands: make(map[string]string),
+		options:  make(map[string]string),
+		scripts:  make(map[string]bool),
+	}
+
+	for _, c := range m.Commands {
+		if c.Name != "" {
+			r.commands[c.Name] = m.Path
+		}
+	}
+	for _, o := range m.Options {
+		r.options[o.Name] = m.Path
+	}
+	for _, s := range m.Scripts {
+		r.scripts[s] = true
+	}
+
+	return r.include(m, []string{m.Path})
+}
+
+func (r *includeResolver) include(parent *Manifest, chain []string) error {
+	for _, i := range parent.Include {
+		path := i
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(parent.BasePath, path)
+		}
+		path = filepath.Clean(path)
+
+		for _, p := range chain {
+			if p == path {
+				return fmt.Errorf("manifest include cycle detected (%s)", r.describeChain(append(chain, path)))
+			}
+		}
+
+		if r.visited[path] {
+			continue
+		}
+		r.visited[path] = true
+
+		m, err := loadManifestFile(path, includedSchema)
+		if err != nil {
+			return fmt.Errorf("failed to include manifest file (path=%s included_by=%s). %v", r.relative(path), r.relative(parent.Path), err)
+		}
+
+		err = r.merge(m)
+		if err != nil {
+			return err
+		}
+
+		err = r.include(m, append(chain, path))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *includeResolver) merge(m *Manifest) error {
+	for _, c := range m.Commands {
+		if c.Name == "" {
+			continue
+		}
+		if origin, ok := r.commands[c.Name]; ok && origin != m.Path {
+			return fmt.Errorf("command \"%s\" is defined in multiple manifest files (%s and %s)", c.Name, r.relative(origin), r.relative(m.Path))
+		}
+		r.commands[c.Name] = m.Path
+	}
+
+	for _, o := range m.Options {
+		if origin, ok := r.options[o.Name]; ok && origin != m.Path {
+			return fmt.Errorf("option \"%s\" is defined in multiple manifest files (%s and %s)", o.Name, r.relative(origin), r.relative(m.Path))
+		}
+		r.options[o.Name] = m.Path
+	}
+
+	for _, s := range m.Scripts {
+		s = r.rebase(m, s)
+		if !r.scripts[s] {
+			r.scripts[s] = true
+			r.root.Scripts = append(r.root.Scripts, s)
+		}
+	}
+
+	for _, c := range m.Commands {
+		c.Path = r.rebase(m, c.Path)
+		c.EnvFiles = r.rebaseEnvFiles(m, c.EnvFiles)
+		c.Options = r.rebaseOptions(m, c.Options)
+		for name, f := range c.Functions {
+			f.Options = r.rebaseOptions(m, f.Options)
+			c.Functions[name] = f
+		}
+		r.root.Commands = append(r.root.Commands, c)
+	}
+
+	r.root.Options = append(r.root.Options, r.rebaseOptions(m, m.Options)...)
+
+	return nil
+}
+
+// rebaseEnvFiles returns a copy of the env files with paths relative to the root manifest
+func (r *includeResolver) rebaseEnvFiles(m *Manifest, files []EnvFile) []EnvFile {
+	if files == nil {
+		return nil
+	}
+
+	rebased := make([]EnvFile, 0, len(files))
+	for _, f := range files {
+		f.Path = r.rebase(m, f.Path)
+		rebased = append(rebased, f)
+	}
+	return rebased
+}
+
+// rebaseOptions returns a copy of the options with path defaults relative to the root manifest
+func (r *includeResolver) rebaseOptions(m *Manifest, options []Option) []Option {
+	if options == nil {
+		return nil
+	}
+
+	rebased := make([]Option, 0, len(options))
+	for _, o := range options {
+		if o.Type == cmd.PathOption {
+			o.Default = r.rebase(m, o.Default)
+		}
+		rebased = append(rebased, o)
+	}
+	return rebased
+}
+
+// rebase makes a path relative to an included manifest relative to the root manifest
+func (r *includeResolver) rebase(m *Manifest, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	rel, err := filepath.Rel(r.root.BasePath, filepath.Join(m.BasePath, path))
+	if err != nil {
+		return filepath.Join(m.BasePath, path)
+	}
+
+	return rel
+}
+
+func (r *includeResolver) relative(path string) string {
+	rel, err := filepath.Rel(r.root.BasePath, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+func (r *includeResolver) describeChain(chain []string) string {
+	files := make([]string, 0, len(chain))
+	for _, p := range chain {
+		files = append(files, r.relative(p))
+	}
+	return strings.Join(files, " -> ")
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// variablePattern matches ${NAME}, ${NAME-default}, ${NAME:-default}, ${NAME?message}, ${NAME:?message}
+// as well as the escape sequence $${ that produces a literal ${
+var variablePattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-?])([^}]*))?\}`)
+
+var templateFuncs = template.FuncMap{
+	"env": os.Getenv,
+}
+
+// interpolateManifest replaces environment variable references and templates
+// in all string values of a manifest (expects json)
+func interpolateManifest(bs []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(bs))
+	d.UseNumber()
+
+	var v interface{}
+	err := d.Decode(&v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to interpolate manifest. %v", err)
+	}
+
+	v, err = interpolateValue(v, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v)
+}
+
+func interpolateValue(v interface{}, path string) (interface{}, error) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+
+			iv, err := interpolateValue(t[k], p)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = iv
+		}
+		return t, nil
+	case []interface{}:
+		for i, e := range t {
+			iv, err := interpolateValue(e, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			t[i] = iv
+		}
+		return t, nil
+	case string:
+		s, err := interpolateString(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to interpolate manifest value (path=%s). %v", path, err)
+		}
+		return s, nil
+	}
+
+	return v, nil
+}
+
+// interpolateString executes templates ({{ env "NAME" }}) and then expands variable references (${NAME})
+func interpolateString(s string) (string, error) {
+	if strings.Contains(s, "{{") {
+		t, err := template.New("value").Funcs(templateFuncs).Option("missingkey=error").Parse(s)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse template. %v", err)
+		}
+
+		var buf bytes.Buffer
+		err = t.Execute(&buf, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to execute template. %v", err)
+		}
+		s = buf.String()
+	}
+
+	var err error
+	s = variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if err != nil {
+			return match
+		}
+		if match == "$${" {
+			return "${"
+		}
+
+		var value string
+		value, err = expandVariable(variablePattern.FindStringSubmatch(match))
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return s, nil
+}
+
+func expandVariable(groups []string) (string, error) {
+	name, operator, word := groups[1], groups[2], groups[3]
+	value, set := os.LookupEnv(name)
+
+	// With a colon, an empty value is treated the same as an unset value
+	if strings.HasPrefix(operator, ":") && value == "" {
+		set = false
+	}
+
+	if set {
+		return value, nil
+	}
+
+	switch strings.TrimPrefix(operator, ":") {
+	case "-":
+		return word, nil
+	case "?":
+		if word == "" {
+			word = "variable is not set"
+		}
+		return "", fmt.Errorf("required variable %s is missing a value (%s)", name, word)
+	}
+
+	return "", nil
+}
@@ -8,7 +8,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/ghodss/yaml"
 	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
 	yaml2 "gopkg.in/yaml.v2"
 )
@@ -17,11 +16,18 @@ const (
 	defaultLogLevel string = "info"
 )
 
+const (
+	manifestSchema string = "bindata://schemas/manifest.json"
+	includedSchema string = "bindata://schemas/manifest.json#/definitions/included"
+)
+
 // Manifest defines the structure of a manifest
 type Manifest struct {
+	Include  []string  `yaml:"include,omitempty"`
 	Scripts  []string  `yaml:"scripts,omitempty"`
 	Commands []Command `yaml:"commands,omitempty"`
 	Options  []Option  `yaml:"options,omitempty"`
+	Profiles []Profile `yaml:"profiles,omitempty"`
 	Config   Config    `yaml:"config,omitempty"`
 	Path     string
 	BasePath string
@@ -29,12 +35,42 @@ type Manifest struct {
 
 // Command defines the structure of commands
 type Command struct {
-	Name        string            `yaml:"name,omitempty"`
-	Path        string            `yaml:"path,omitempty"`
-	Description string            `yaml:"description,omitempty"`
-	Help        string            `yaml:"help,omitempty"`
-	Annotations map[string]string `yaml:"annotations,omitempty"`
-	Hidden      bool              `yaml:"hidden,omitempty"`
+	Name        string                     `yaml:"name,omitempty"`
+	Path        string                     `yaml:"path,omitempty"`
+	Aliases     []string                   `yaml:"aliases,omitempty"`
+	Description string                     `yaml:"description,omitempty"`
+	Help        string                     `yaml:"help,omitempty"`
+	Category    string                     `yaml:"category,omitempty"`
+	Options     []Option                   `yaml:"options,omitempty"`
+	Functions   map[string]CommandFunction `yaml:"functions,omitempty"`
+	Env         map[string]string          `yaml:"env,omitempty"`
+	EnvFiles    []EnvFile                  `yaml:"envFiles,omitempty"`
+	Requires    []Requirement              `yaml:"requires,omitempty"`
+	Annotations map[string]string          `yaml:"annotations,omitempty"`
+	Hidden      bool                       `yaml:"hidden,omitempty"`
+}
+
+// CommandFunction defines the structure of overrides for a single function (sub-command) of a command
+type CommandFunction struct {
+	Options []Option `yaml:"options,omitempty"`
+}
+
+// HasGlobPath returns true if the path of the command is a glob pattern
+func (c Command) HasGlobPath() bool {
+	return strings.ContainsAny(c.Path, "*?[")
+}
+
+// HasNameOrAlias returns true if the name or one of the aliases of the command matches name
+func (c Command) HasNameOrAlias(name string) bool {
+	if c.Name == name {
+		return true
+	}
+	for _, a := range c.Aliases {
+		if a == name {
+			return true
+		}
+	}
+	return false
 }
 
 // Annotation returns a parsed annotation if present
@@ -48,12 +84,23 @@ type Option struct {
 	Name        string            `yaml:"name,omitempty"`
 	Short       string            `yaml:"short,omitempty"`
 	EnvName     string            `yaml:"env_name,omitempty"`
+	FromEnv     string            `yaml:"from_env,omitempty"`
 	Values      []OptionValue     `yaml:"values,omitempty"`
 	Default     string            `yaml:"default,omitempty"`
 	Required    bool              `yaml:"required,omitempty"`
+	Secret      bool              `yaml:"secret,omitempty"`
 	Description string            `yaml:"description,omitempty"`
 	Annotations map[string]string `yaml:"annotations,omitempty"`
 	Hidden      bool              `yaml:"hidden,omitempty"`
+	MustExist   bool              `yaml:"must_exist,omitempty"`
+	Kind        string            `yaml:"kind,omitempty"`
+	Extensions  []string          `yaml:"extensions,omitempty"`
+	Pattern     string            `yaml:"pattern,omitempty"`
+	Min         *int              `yaml:"min,omitempty"`
+	Max         *int              `yaml:"max,omitempty"`
+	MinLength   *int              `yaml:"min_length,omitempty"`
+	MaxLength   *int              `yaml:"max_length,omitempty"`
+	OneOf       []string          `yaml:"one_of,omitempty"`
 }
 
 type OptionValue struct {
@@ -67,16 +114,48 @@ func (o Option) Annotation(namespace, key string) (*Annotation, error) {
 	return ParseAnnotation(getAnnotationString(o.Annotations, namespace, key))
 }
 
+// Profile defines the structure of profiles
+type Profile struct {
+	Name        string            `yaml:"name,omitempty"`
+	Description string            `yaml:"description,omitempty"`
+	Defaults    map[string]string `yaml:"defaults,omitempty"`
+	Env         map[string]string `yaml:"env,omitempty"`
+}
+
+// Profile returns the profile with the given name or nil when not found
+func (m Manifest) Profile(name string) *Profile {
+	for i, p := range m.Profiles {
+		if p.Name == name {
+			return &m.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// ProfileNames returns the names of all profiles
+func (m Manifest) ProfileNames() []string {
+	names := make([]string, 0, len(m.Profiles))
+	for _, p := range m.Profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
 // Config defines the structure for the configuration section
 type Config struct {
-	Name                 string    `yaml:"name,omitempty"`
-	Description          string    `yaml:"description,omitempty"`
-	Version              string    `yaml:"version,omitempty"`
-	Log                  LogConfig `yaml:"log,omitempty"`
-	EnvironmentPrefix    string    `yaml:"environmentPrefix,omitempty"`
-	HideInternalCommands bool      `yaml:"hideInternalCommands,omitempty"`
-	HideInternalOptions  bool      `yaml:"hideInternalOptions,omitempty"`
-	HelpMode             HelpMode  `yaml:"helpMode,omitempty"`
+	Name                 string            `yaml:"name,omitempty"`
+	Description          string            `yaml:"description,omitempty"`
+	Version              string            `yaml:"version,omitempty"`
+	CentryVersion        string            `yaml:"centryVersion,omitempty"`
+	Log                  LogConfig         `yaml:"log,omitempty"`
+	EnvironmentPrefix    string            `yaml:"environmentPrefix,omitempty"`
+	Env                  map[string]string `yaml:"env,omitempty"`
+	EnvFiles             []EnvFile         `yaml:"envFiles,omitempty"`
+	Requires             []Requirement     `yaml:"requires,omitempty"`
+	HideInternalCommands bool              `yaml:"hideInternalCommands,omitempty"`
+	HideInternalOptions  bool              `yaml:"hideInternalOptions,omitempty"`
+	HelpMode             HelpMode          `yaml:"helpMode,omitempty"`
+	FunctionDiscovery    FunctionDiscovery `yaml:"functionDiscovery,omitempty"`
 }
 
 type HelpMode string
@@ -86,6 +165,16 @@ const (
 	HelpModeInteractive HelpMode = "interactive"
 )
 
+// FunctionDiscovery defines how functions are discovered in scripts
+type FunctionDiscovery string
+
+const (
+	// FunctionDiscoverySource sources the script in bash and lists the declared functions
+	FunctionDiscoverySource FunctionDiscovery = "source"
+	// FunctionDiscoveryStatic parses the script without executing it
+	FunctionDiscoveryStatic FunctionDiscovery = "static"
+)
+
 // LogConfig defines the structure for log configuration section
 type LogConfig struct {
 	Level  string `yaml:"level,omitempty"`
@@ -94,25 +183,79 @@ type LogConfig struct {
 
 // LoadManifest reads, parses and returns a manifest root object
 func LoadManifest(manifest string) (*Manifest, error) {
+	m, err := loadManifestFile(manifest, manifestSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resolveIncludes(m)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateManifestCommands(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func loadManifestFile(manifest string, schema string) (*Manifest, error) {
 	mp, _ := filepath.Abs(manifest)
 
+	decode, err := manifestDecoderFor(manifest)
+	if err != nil {
+		return nil, err
+	}
+
 	bs, err := readManifestFile(manifest)
 	if err != nil {
 		return nil, err
 	}
 
-	m, err := parseManifestYaml(bs)
+	jbs, err := decode(bs)
 	if err != nil {
 		return nil, err
 	}
 
-	jbs, err := yaml.YAMLToJSON(bs)
+	jbs, err = interpolateManifest(jbs)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := parseManifestYaml(jbs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateCentryVersion(m, manifest)
 	if err != nil {
 		return nil, err
 	}
 
 	r := bytes.NewReader(jbs)
-	err = validateManifestYaml("bindata://schemas/manifest.json", r)
+	err = validateManifestYaml(schema, r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateManifestCommands(m)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateManifestOptions(m)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateManifestProfiles(m)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateManifestRequirements(m)
 	if err != nil {
 		return nil, err
 	}
@@ -124,10 +267,6 @@ func LoadManifest(manifest string) (*Manifest, error) {
 }
 
 func readManifestFile(filepath string) ([]byte, error) {
-	if !strings.HasSuffix(filepath, ".yaml") && !strings.HasSuffix(filepath, ".yml") {
-		return nil, fmt.Errorf("manifest file must have a yaml or yml extension (path=%s)", filepath)
-	}
-
 	if _, err := os.Stat(filepath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("manifest file not found (path=%s)", filepath)
 	}
@@ -157,6 +296,93 @@ func parseManifestYaml(bs []byte) (*Manifest, error) {
 	return &m, nil
 }
 
+func validateManifestCommands(m *Manifest) error {
+	names := make(map[string]string)
+	for _, c := range m.Commands {
+		if c.Name != "" {
+			names[c.Name] = c.Name
+		}
+	}
+
+	for _, c := range m.Commands {
+		for _, alias := range c.Aliases {
+			if other, ok := names[alias]; ok && other != c.Name {
+				return fmt.Errorf("command alias collides with another command (command=%s alias=%s other=%s)", c.Name, alias, other)
+			}
+			names[alias] = c.Name
+		}
+	}
+
+	for _, c := range m.Commands {
+		if c.HasGlobPath() && c.Name != "" {
+			return fmt.Errorf("command name must not be set when path is a glob pattern (name=%s path=%s)", c.Name, c.Path)
+		}
+		if c.HasGlobPath() && len(c.Aliases) > 0 {
+			return fmt.Errorf("command aliases must not be set when path is a glob pattern (path=%s)", c.Path)
+		}
+		if !c.HasGlobPath() && c.Name == "" {
+			return fmt.Errorf("command name is required unless path is a glob pattern (path=%s)", c.Path)
+		}
+	}
+	return nil
+}
+
+func validateManifestOptions(m *Manifest) error {
+	options := append([]Option{}, m.Options...)
+	for _, c := range m.Commands {
+		options = append(options, c.Options...)
+		for _, f := range c.Functions {
+			options = append(options, f.Options...)
+		}
+	}
+
+	for _, o := range options {
+		if o.Secret && o.Type != cmd.StringOption {
+			return fmt.Errorf("secret is only supported by string options (option=%s type=%s)", o.Name, o.Type)
+		}
+
+		if o.Default == "" {
+			continue
+		}
+
+		values := make([]cmd.OptionValue, 0, len(o.Values))
+		for _, v := range o.Values {
+			values = append(values, cmd.OptionValue{Name: v.Name})
+		}
+
+		option := &cmd.Option{Type: o.Type, Name: o.Name, Default: o.Default, Values: values}
+		if err := cmd.NewOptionsSet(o.Name).Add(option); err != nil {
+			return fmt.Errorf("invalid default value for option \"%s\" (type=%s default=%s). %v", o.Name, o.Type, o.Default, err)
+		}
+	}
+	return nil
+}
+
+func validateManifestProfiles(m *Manifest) error {
+	names := make(map[string]bool)
+	for _, p := range m.Profiles {
+		if names[p.Name] {
+			return fmt.Errorf("profile \"%s\" is defined multiple times", p.Name)
+		}
+		names[p.Name] = true
+	}
+	return nil
+}
+
+func validateManifestRequirements(m *Manifest) error {
+	requirements := append([]Requirement{}, m.Config.Requires...)
+	for _, c := range m.Commands {
+		requirements = append(requirements, c.Requires...)
+	}
+
+	for _, r := range requirements {
+		if _, err := r.Constraints(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getAnnotationString(annotations map[string]string, namespace, key string) string {
 	if annotations == nil {
 		return ""
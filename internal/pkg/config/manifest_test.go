@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -39,11 +40,276 @@ func TestManifest(t *testing.T) {
 			g.Assert(err.Error()).Equal("manifest file not found (path=foo.yaml)")
 		})
 
-		g.It("returns error when path is not a yaml or yml file", func() {
+		g.It("returns error when path is not a yaml, yml, json or toml file", func() {
 			m, err := LoadManifest("foo.bar")
 			g.Assert(m == nil).IsTrue("exected manifest to be nil")
 			g.Assert(err != nil).IsTrue("expected error")
-			g.Assert(err.Error()).Equal("manifest file must have a yaml or yml extension (path=foo.bar)")
+			g.Assert(err.Error()).Equal("manifest file must have a json, toml, yaml or yml extension (path=foo.bar)")
+		})
+	})
+
+	g.Describe("centry version", func() {
+		var centryVersion string
+
+		g.BeforeEach(func() {
+			centryVersion = CentryVersion
+		})
+
+		g.AfterEach(func() {
+			CentryVersion = centryVersion
+		})
+
+		g.It("returns error before schema validation when centry version does not satisfy the constraint", func() {
+			CentryVersion = "1.3.0"
+			m, err := LoadManifest("test/data/manifest_test_centry_version.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(strings.HasPrefix(err.Error(), "the manifest requires a different version of centry (centryVersion=>=999.0.0 version=1.3.0 path=test/data/manifest_test_centry_version.yaml)")).IsTrue(err.Error())
+		})
+
+		g.It("skips check for development builds", func() {
+			CentryVersion = "dev"
+			_, err := LoadManifest("test/data/manifest_test_centry_version.yaml")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(strings.Contains(err.Error(), "centry version")).IsFalse(err.Error())
+		})
+
+		g.It("returns error when constraint is invalid", func() {
+			_, err := LoadManifest("test/data/manifest_test_centry_version_invalid.yaml")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(strings.HasPrefix(err.Error(), "invalid centry version constraint (centryVersion=>=nope path=test/data/manifest_test_centry_version_invalid.yaml)")).IsTrue(err.Error())
+		})
+
+		g.It("returns manifest when centry version satisfies the constraint", func() {
+			CentryVersion = "1.3.0"
+			m, err := LoadManifest("test/data/manifest_test_valid.yaml")
+			g.Assert(err == nil).IsTrue("expected error to be nil, %v", err)
+			g.Assert(m.Config.CentryVersion).Equal(">=1.0.0")
+		})
+	})
+
+	g.Describe("formats", func() {
+		for _, ext := range []string{"yaml", "json", "toml"} {
+			ext := ext
+
+			g.It(fmt.Sprintf("returns manifest for valid %s file", ext), func() {
+				m, err := LoadManifest(fmt.Sprintf("test/data/manifest_test_valid.%s", ext))
+				g.Assert(err == nil).IsTrue("expected error to be nil, %v", err)
+				g.Assert(m.Config.Name).Equal("centry")
+				g.Assert(m.Config.Version).Equal("1.0.0")
+				g.Assert(m.Config.Log.Level).Equal("debug")
+				g.Assert(m.Scripts).Equal([]string{"scripts/init.sh"})
+				g.Assert(m.Commands[0].Name).Equal("get")
+				g.Assert(m.Commands[0].Path).Equal("commands/get.sh")
+				g.Assert(m.Options[0].Name).Equal("stringopt")
+				g.Assert(m.Options[0].Default).Equal("foobar")
+			})
+		}
+
+		for _, ext := range []string{"json", "toml"} {
+			ext := ext
+
+			g.It(fmt.Sprintf("returns validation error for invalid %s file", ext), func() {
+				_, err := LoadManifest(fmt.Sprintf("test/data/manifest_test_invalid.%s", ext))
+				g.Assert(err != nil).IsTrue("expected validation error")
+				g.Assert(err.Error()).Equal("invalid manifest file\n\nI[#/config/name] S[#/properties/config/properties/name/minLength] length must be >= 1, but got 0")
+			})
+		}
+
+		g.It("returns error when json is invalid", func() {
+			_, err := decodeJSON([]byte("{"))
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(strings.HasPrefix(err.Error(), "failed to parse manifest json")).IsTrue("expected error message")
+		})
+
+		g.It("returns error when toml is invalid", func() {
+			_, err := decodeToml([]byte("config = {"))
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(strings.HasPrefix(err.Error(), "failed to parse manifest toml")).IsTrue("expected error message")
+		})
+	})
+
+	g.Describe("interpolation", func() {
+		g.AfterEach(func() {
+			os.Unsetenv("CENTRY_TEST_THING")
+			os.Unsetenv("CENTRY_TEST_USER")
+			os.Unsetenv("CENTRY_TEST_RELEASE_VERSION")
+			os.Unsetenv("CENTRY_TEST_REQUIRED")
+		})
+
+		g.It("uses default values when variables are not set", func() {
+			m, err := LoadManifest("test/data/manifest_test_interpolation.yaml")
+			g.Assert(err == nil).IsTrue("expected error to be nil, %v", err)
+			g.Assert(m.Commands[0].Description).Equal("Gets stuff")
+			g.Assert(m.Options[0].Default).Equal("nobody")
+			g.Assert(m.Options[1].Default).Equal("${NOT_INTERPOLATED}")
+			g.Assert(m.Config.Version).Equal("dev")
+		})
+
+		g.It("uses environment variables when set", func() {
+			os.Setenv("CENTRY_TEST_THING", "things")
+			os.Setenv("CENTRY_TEST_USER", "jane")
+			os.Setenv("CENTRY_TEST_RELEASE_VERSION", "1.2.3")
+
+			m, err := LoadManifest("test/data/manifest_test_interpolation.yaml")
+			g.Assert(err == nil).IsTrue("expected error to be nil, %v", err)
+			g.Assert(m.Commands[0].Description).Equal("Gets things")
+			g.Assert(m.Options[0].Default).Equal("jane")
+			g.Assert(m.Config.Version).Equal("1.2.3")
+		})
+
+		g.It("returns error pointing at the value when a required variable is missing", func() {
+			m, err := LoadManifest("test/data/manifest_test_interpolation_required.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("failed to interpolate manifest value (path=commands[1].description). required variable CENTRY_TEST_REQUIRED is missing a value (must be set to describe put)")
+		})
+
+		g.It("expands variables", func() {
+			os.Setenv("CENTRY_TEST_THING", "")
+
+			s, _ := interpolateString("${CENTRY_TEST_THING-unset}")
+			g.Assert(s).Equal("")
+			s, _ = interpolateString("${CENTRY_TEST_THING:-empty}")
+			g.Assert(s).Equal("empty")
+			s, _ = interpolateString("${CENTRY_TEST_USER}")
+			g.Assert(s).Equal("")
+			s, _ = interpolateString("$HOME and ${incomplete")
+			g.Assert(s).Equal("$HOME and ${incomplete")
+
+			_, err := interpolateString("${CENTRY_TEST_THING?}")
+			g.Assert(err == nil).IsTrue("expected error to be nil, %v", err)
+			_, err = interpolateString("${CENTRY_TEST_THING:?}")
+			g.Assert(err.Error()).Equal("required variable CENTRY_TEST_THING is missing a value (variable is not set)")
+		})
+
+		g.It("returns error for invalid templates", func() {
+			_, err := interpolateString("{{ env }")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(strings.HasPrefix(err.Error(), "failed to parse template")).IsTrue("expected error message, got %v", err)
+		})
+	})
+
+	g.Describe("commands", func() {
+		g.It("returns error when command name is missing and path is not a glob pattern", func() {
+			m, err := LoadManifest("test/data/manifest_test_command_missing_name.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("command name is required unless path is a glob pattern (path=commands/get.sh)")
+		})
+
+		g.It("returns error when command name is set and path is a glob pattern", func() {
+			m, err := LoadManifest("test/data/manifest_test_command_glob_with_name.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("command name must not be set when path is a glob pattern (name=get path=commands/*.sh)")
+		})
+
+		g.It("returns error when command aliases are set and path is a glob pattern", func() {
+			m, err := LoadManifest("test/data/manifest_test_command_glob_with_aliases.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("command aliases must not be set when path is a glob pattern (path=commands/*.sh)")
+		})
+
+		g.It("returns error when a command alias collides with the alias of another command", func() {
+			m, err := LoadManifest("test/data/manifest_test_command_alias_collision.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("command alias collides with another command (command=generate alias=g other=get)")
+		})
+
+		g.It("returns error when a command alias collides with the name of another command", func() {
+			m, err := LoadManifest("test/data/manifest_test_command_alias_name_collision.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("command alias collides with another command (command=fetch alias=get other=get)")
+		})
+	})
+
+	g.Describe("options", func() {
+		g.It("returns error when the default value of a bool option is invalid", func() {
+			m, err := LoadManifest("test/data/manifest_test_option_default_invalid.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("invalid default value for option \"verbose\" (type=bool default=yes). strconv.ParseBool: parsing \"yes\": invalid syntax")
+		})
+
+		g.It("returns error when the default value of a select/v2 option is not one of its values", func() {
+			m, err := LoadManifest("test/data/manifest_test_option_default_not_a_value.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("invalid default value for option \"env\" (type=select/v2 default=prod). default value \"prod\" is not a value of option \"env\"")
+		})
+
+		g.It("returns error when an option that is not a string is secret", func() {
+			m, err := LoadManifest("test/data/manifest_test_option_secret_invalid.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("secret is only supported by string options (option=retries type=integer)")
+		})
+	})
+
+	g.Describe("include", func() {
+		g.It("merges commands, options and scripts from included manifest files", func() {
+			m, err := LoadManifest("test/data/manifest_test_include.yaml")
+			g.Assert(err == nil).IsTrue("expected error to be nil, %v", err)
+
+			commands := make([]string, 0)
+			paths := make([]string, 0)
+			for _, c := range m.Commands {
+				commands = append(commands, c.Name)
+				paths = append(paths, c.Path)
+			}
+			g.Assert(commands).Equal([]string{"get", "team-a", "team-c", "team-b"})
+			g.Assert(paths).Equal([]string{"commands/get.sh", "include/commands/team_a.sh", "include/nested/commands/team_c.sh", "include/commands/team_b.sh"})
+
+			options := make([]string, 0)
+			for _, o := range m.Options {
+				options = append(options, o.Name)
+			}
+			g.Assert(options).Equal([]string{"stringopt", "team-a-opt"})
+
+			g.Assert(m.Scripts).Equal([]string{"scripts/init.sh", "include/scripts/team_a.sh"})
+		})
+
+		g.It("rebases env files and path option defaults of included commands", func() {
+			m, err := LoadManifest("test/data/manifest_test_include.yaml")
+			g.Assert(err == nil).IsTrue("expected error to be nil, %v", err)
+
+			c := m.Commands[1]
+			g.Assert(c.Name).Equal("team-a")
+			g.Assert(c.EnvFiles).Equal([]EnvFile{{Path: "include/team_a.env", Optional: true}})
+			g.Assert(c.Options[0].Default).Equal("include/commands")
+		})
+
+		g.It("returns error when includes form a cycle", func() {
+			m, err := LoadManifest("test/data/manifest_test_include_cycle.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("manifest include cycle detected (manifest_test_include_cycle.yaml -> include/cycle_a.yaml -> include/cycle_b.yaml -> include/cycle_a.yaml)")
+		})
+
+		g.It("returns error when a command is defined in multiple files", func() {
+			m, err := LoadManifest("test/data/manifest_test_include_conflict.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(err.Error()).Equal("command \"team-b\" is defined in multiple manifest files (manifest_test_include_conflict.yaml and include/conflict.yaml)")
+		})
+
+		g.It("returns error when an included manifest file is invalid", func() {
+			m, err := LoadManifest("test/data/manifest_test_include_invalid.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(strings.HasPrefix(err.Error(), "failed to include manifest file (path=include/invalid.yaml included_by=manifest_test_include_invalid.yaml). invalid manifest file")).IsTrue("expected error message, got %v", err)
+		})
+
+		g.It("returns error when an included manifest file contains keys only allowed in the root manifest", func() {
+			m, err := LoadManifest("test/data/manifest_test_include_invalid_keys.yaml")
+			g.Assert(m == nil).IsTrue("exected manifest to be nil")
+			g.Assert(err != nil).IsTrue("expected error")
+			g.Assert(strings.HasPrefix(err.Error(), "failed to include manifest file (path=include/invalid_keys.yaml included_by=manifest_test_include_invalid_keys.yaml). invalid manifest file")).IsTrue("expected error message, got %v", err)
+			g.Assert(strings.Contains(err.Error(), "config")).IsTrue("expected error to mention config, got %v", err)
 		})
 	})
 
@@ -80,6 +346,18 @@ func TestManifest(t *testing.T) {
 	})
 
 	g.Describe("command", func() {
+		g.Describe("glob path", func() {
+			g.It("returns true when path contains glob characters", func() {
+				g.Assert(Command{Path: "commands/*.sh"}.HasGlobPath()).IsTrue()
+				g.Assert(Command{Path: "commands/get?.sh"}.HasGlobPath()).IsTrue()
+				g.Assert(Command{Path: "commands/[gs]et.sh"}.HasGlobPath()).IsTrue()
+			})
+
+			g.It("returns false when path is a plain path", func() {
+				g.Assert(Command{Path: "commands/get.sh"}.HasGlobPath()).IsFalse()
+			})
+		})
+
 		g.Describe("annotations", func() {
 			g.It("returns nil when command has no annotations", func() {
 				c := Command{}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9._+-]+)\s*(.*)$`)
+
+var constraintOperatorPattern = regexp.MustCompile(`^[<>=~^!]`)
+
+// Requirement defines the structure of an external tool (binary) required to run commands
+type Requirement struct {
+	Name           string `yaml:"name,omitempty"`
+	Version        string `yaml:"version,omitempty"`
+	VersionCommand string `yaml:"versionCommand,omitempty"`
+}
+
+// String returns the requirement as a string (name and version constraint)
+func (r Requirement) String() string {
+	if r.Version == "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%s %s", r.Name, r.Version)
+}
+
+// Constraints returns the parsed version constraint, nil when no version constraint is set
+func (r Requirement) Constraints() (*semver.Constraints, error) {
+	if r.Version == "" {
+		return nil, nil
+	}
+
+	c, err := semver.NewConstraint(r.Version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version constraint for requirement \"%s\" (version=%s). %v", r.Name, r.Version, err)
+	}
+	return c, nil
+}
+
+// ParseRequirement parses a requirement from a string like "kubectl >=1.25"
+func ParseRequirement(s string) (Requirement, error) {
+	m := requirementPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Requirement{}, fmt.Errorf("invalid requirement \"%s\"", s)
+	}
+
+	r := Requirement{Name: m[1], Version: strings.TrimSpace(m[2])}
+	if _, err := r.Constraints(); err != nil {
+		return Requirement{}, err
+	}
+
+	return r, nil
+}
+
+// ParseRequirements parses a comma separated list of requirements, commas separating version constraints (AND) of a requirement are kept
+func ParseRequirements(value string) ([]Requirement, error) {
+	items := make([]string, 0)
+	for _, v := range ParseAnnotationList(value) {
+		if len(items) > 0 && isConstraintContinuation(v) {
+			items[len(items)-1] = fmt.Sprintf("%s, %s", items[len(items)-1], v)
+			continue
+		}
+		items = append(items, v)
+	}
+
+	requirements := make([]Requirement, 0)
+	for _, v := range items {
+		r, err := ParseRequirement(v)
+		if err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, r)
+	}
+	return requirements, nil
+}
+
+// isConstraintContinuation returns true if a list item continues the version constraint of the previous requirement (e.g. "<2" in "kubectl >=1.25, <2")
+func isConstraintContinuation(v string) bool {
+	if constraintOperatorPattern.MatchString(v) {
+		return true
+	}
+	if v[0] >= '0' && v[0] <= '9' {
+		_, err := semver.NewConstraint(v)
+		return err == nil
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestRequirements(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("ParseRequirement", func() {
+		g.It("returns requirement without version constraint", func() {
+			r, err := ParseRequirement("jq")
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(r.Name).Equal("jq")
+			g.Assert(r.Version).Equal("")
+			g.Assert(r.String()).Equal("jq")
+		})
+
+		g.It("returns requirement with version constraint", func() {
+			r, err := ParseRequirement(" kubectl >=1.25, <2 ")
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(r.Name).Equal("kubectl")
+			g.Assert(r.Version).Equal(">=1.25, <2")
+		})
+
+		g.It("returns error when version constraint is invalid", func() {
+			_, err := ParseRequirement("kubectl ~>nope")
+			g.Assert(err != nil).IsTrue("expected error")
+		})
+
+		g.It("returns error when requirement is empty", func() {
+			_, err := ParseRequirement("")
+			g.Assert(err != nil).IsTrue("expected error")
+		})
+	})
+
+	g.Describe("ParseRequirements", func() {
+		g.It("returns requirements from comma separated list", func() {
+			r, err := ParseRequirements("bash >=4, jq")
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(len(r)).Equal(2)
+			g.Assert(r[0].String()).Equal("bash >=4")
+			g.Assert(r[1].String()).Equal("jq")
+		})
+
+		g.It("keeps comma separated version constraints with the requirement", func() {
+			r, err := ParseRequirements("kubectl >=1.25, <2, jq, helm >=3, 3.12, 7z")
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(len(r)).Equal(4)
+			g.Assert(r[0].String()).Equal("kubectl >=1.25, <2")
+			g.Assert(r[1].String()).Equal("jq")
+			g.Assert(r[2].String()).Equal("helm >=3, 3.12")
+			g.Assert(r[3].String()).Equal("7z")
+		})
+
+		g.It("returns error when a continued version constraint is invalid", func() {
+			_, err := ParseRequirements("kubectl >=1.25, <nope")
+			g.Assert(err != nil).IsTrue("expected error")
+		})
+	})
+}
@@ -1,6 +1,6 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
-// schemas/manifest.json (3.932kB)
+// schemas/manifest.json (9.253kB)
 
 package config
 
@@ -68,7 +68,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _schemasManifestJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x57\xcd\x8e\xd3\x30\x10\xbe\xf7\x29\x22\xc3\x71\x77\x23\x38\xf6\xca\x09\x09\x04\x77\x54\x21\x37\x99\x24\x5e\x9c\x71\x18\x3b\x61\x2b\xd4\x77\x47\x71\xb2\x6d\xe2\x9f\xb4\xa5\xe4\xc0\xa9\xd5\xfc\x7c\x9e\xf1\xcc\x7c\xe3\xfc\xde\x24\x09\x7b\xab\xb3\x0a\x6a\xce\xb6\x09\xab\x8c\x69\xb6\x69\xfa\xac\x15\x3e\x0e\xd2\x27\x45\x65\x3a\xfc\x7d\xc3\x1e\xac\xb9\xc8\x5f\x4d\xf5\x36\x4d\x4b\x61\xaa\x76\xff\x94\xa9\x3a\xfd\x41\x42\x1b\x55\x14\x40\xbc\x92\x69\xa9\x1e\x33\x40\x43\x87\xd1\x5d\xa7\x35\x47\x51\x80\x36\x4f\x3d\xfe\x00\x66\x0e\x0d\xf4\x68\x6a\xff\x0c\x99\x19\x64\x0d\xa9\x06\xc8\x08\xd0\x6c\x9b\xf4\x11\x26\x09\xd3\x19\x89\xc6\x9c\x05\x13\x57\x4e\xc4\x0f\xd6\xd3\x8a\x85\x81\x7a\x6a\x37\xb1\xd4\x86\x04\x96\x6c\x54\x1c\xed\xef\x71\x70\x64\x99\xaa\x6b\x8e\xf9\x9d\x27\x4c\xd2\x18\x35\x81\x64\x46\x0d\xf2\x1a\x1c\x99\x1f\xeb\xc3\x5c\x5b\x0b\xfc\x04\x58\x9a\x8a\x6d\x93\x77\x13\xd5\x71\x6a\xc7\x1a\x6e\x0d\x56\x40\xae\x40\x36\xeb\x20\xe7\x30\x94\x58\x28\x5c\xe7\x00\x8e\xa8\x0c\xef\xf1\xdd\x4a\xf8\xf5\x8b\xe7\x2f\xf2\x1c\x16\x02\xdc\x2b\x25\x81\xe3\x0c\x60\x13\x80\x62\x04\x3f\x5b\x41\xd0\x4f\xd2\x37\xaf\x29\xfc\x62\x9e\x04\xbb\x60\xf3\xaa\xc6\xcd\x6b\xe5\xde\x1d\x7d\x6e\x2a\x13\x60\x5b\x3b\xe9\x5a\x79\xd0\x3a\x19\xee\xd2\x97\x0a\x34\x50\x02\xf9\x0a\x0d\x72\x1e\xfe\x4c\x9e\x76\xef\xd9\x4c\xb3\x8b\xd6\x78\xbd\xb9\xd4\x95\x22\x73\x17\xb4\xab\xe3\x2f\x57\x1c\x0b\xd8\x7d\x5f\x2f\xa9\xd5\x07\xb7\xe3\xb2\xf5\x3a\x30\xda\xe1\xa3\xd2\xef\x73\xc7\xcb\xeb\xf6\x51\x1f\xed\xf9\x51\x1f\xbc\xc8\x6b\xb2\xbd\x90\x73\x20\xf3\xd1\x23\xdc\x35\xab\x1e\x69\xaf\x7c\xc5\x23\x37\x17\x42\x88\xd1\xe3\xa8\xb5\x35\x70\xc4\x3b\x0f\x23\x1e\xc3\x71\xa1\x99\x0b\xde\xca\xfb\x66\x34\x0a\x3e\x49\xea\x86\xf5\xf1\x1f\x2e\x31\x0b\xe4\x73\xea\x85\x25\x96\x29\x2c\x44\x19\xda\x61\xce\xac\xc6\x66\x34\x34\x9b\x4b\x85\x8b\x94\x6d\x9a\x61\x9c\xdc\xee\x04\xee\x80\xf4\x3f\x07\x95\xaa\x8c\x01\x06\xe8\x6e\x89\xea\x98\x84\x0e\xe4\x12\x7f\xc6\xb6\x76\x64\xcb\xdb\xbb\xdc\xb7\x21\xba\x60\x02\x0b\x15\x92\xff\xe2\x84\x21\x39\x10\x29\xff\x01\x60\x9f\x4a\x28\x32\x8f\x18\xe6\xa3\xef\x4c\x6e\x43\x50\x88\x97\xbf\x49\xf4\x4a\x7a\x09\x16\x0a\xb0\x13\xa4\xb0\x06\x34\x5f\xc9\x3f\xff\xce\x36\xa8\x44\x0e\x1f\xd1\x00\x21\x97\x1f\xfc\xcf\x9a\x64\x71\xd6\x63\x40\x5f\xbc\x27\xe6\x0d\x38\x20\x9b\xcf\x2a\xbf\x69\x36\x83\x7d\x74\xe2\x67\x77\xdb\xf7\x41\xf2\xcc\x88\x6e\xb6\x17\xce\xa5\x7f\x2d\xc3\x29\xaa\x30\x77\xcd\x78\x6a\xf0\xee\x3d\xad\x97\xef\x71\xfe\x66\x9c\x33\xd8\xa6\xf7\x3d\x6e\xfe\x04\x00\x00\xff\xff\x21\x72\xc4\x93\x5c\x0f\x00\x00")
+var _schemasManifestJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x59\x4b\x8f\xdb\xb6\x13\xbf\xeb\x53\x08\x4c\x4e\x7f\xec\xae\xf2\xef\xad\xbe\x15\x2d\x02\x04\x68\xd1\x9c\x7a\x68\xb0\x35\xb8\xd2\xc8\x66\x22\x91\x2a\x49\xb9\xde\x06\xfe\xee\x05\xf5\xb2\x48\x0e\x25\xf9\xa1\x3e\xe0\x3d\xd8\xa3\x99\xdf\x3c\x39\x33\xd4\x7e\x8d\xe2\x98\xbc\x55\xe9\x1e\x4a\x4a\x36\x31\xd9\x6b\x5d\x6d\x92\xe4\xb3\x12\xfc\xb1\xa5\x3e\x09\xb9\x4b\xda\xaf\x6f\xc8\x43\xc3\xce\xb2\x9e\x55\x6d\x92\x64\xc7\xf4\xbe\x7e\x79\x4a\x45\x99\x7c\x91\x4c\x69\x91\xe7\x20\xe9\xbe\x48\x76\xe2\x31\x05\xae\xe5\x6b\x27\xae\x92\x92\x72\x96\x83\xd2\x4f\x06\xbf\x05\xd3\xaf\x15\x18\x34\xf1\xf2\x19\x52\xdd\xd2\x2a\x29\x2a\x90\x9a\x81\x22\x9b\xd8\x58\x18\xc7\x84\xf1\xb4\xa8\x33\x18\x08\xc6\x0e\x09\xb9\x11\x7d\x93\x64\x90\x33\xce\x34\x13\x5c\x25\x3d\x63\x23\x76\x32\x78\x71\x4c\x54\x2a\x59\xa5\xd5\xbc\x74\xcf\x68\x49\xa7\xa2\x2c\x29\xcf\x16\x88\x0f\x9c\x96\xbc\xa8\x1a\xd3\xe6\xc5\x7b\x46\x4b\xba\x92\x22\x67\x05\x2c\x10\x1f\x38\x2d\xf9\x54\xf0\x9c\xed\xc6\xd2\x48\xcc\xe3\x18\x8f\xbb\xf9\x10\x4e\xcb\x71\xe0\x2d\x0c\xa5\x25\xe3\xbb\x01\xc3\xfc\x91\x92\xf1\x1f\x81\xef\xf4\x9e\x6c\xe2\xff\x0f\x0f\x3a\x7b\xcc\x1f\xc9\xa0\x0d\x34\x13\xfc\xbe\xc0\x07\x90\xea\xee\xa0\x6d\x15\xff\xb2\x06\x74\x21\x76\x21\x40\x27\x3b\x53\x19\x32\x1f\x52\xc0\x01\x0a\x8f\x3c\x6d\xa1\xf9\x10\xe0\x75\x49\x36\xf1\x27\x87\xde\xa4\xe9\xa5\xf6\x05\x9a\xd3\x98\x0b\x8c\xfe\x07\x95\x1c\xa3\x83\x94\x42\x62\x0f\x2a\xca\x59\x4a\x1c\xfa\xb3\xf5\x7b\x14\xae\x2e\x06\x90\xb3\xe3\x35\x8e\xe2\xe9\x30\x9f\x53\x84\x7d\x1f\x27\x0a\xf8\x81\x49\xc1\x4b\xe0\xfa\xa3\xf4\xf5\xdf\x58\x06\xc0\x0f\x2e\x20\x7e\xc4\x0d\x63\x08\xe1\xbd\xd3\x25\xa6\x61\xde\x9f\x3b\x85\x8b\x25\xe1\xf7\x9a\xc9\xa5\x58\x03\x37\x8a\xb5\x67\x19\x7c\xe0\x1a\x24\xa7\xc5\xf7\x7e\x1f\xb5\x62\xf7\x22\x44\x01\x94\xcf\x03\xfd\xec\x35\xd4\x0b\x70\xa0\xa8\x7e\x12\x19\x84\x84\xb1\x04\xa2\x27\x84\x64\x90\xd3\xba\xb0\x4f\x68\x73\x36\x34\x48\x9a\x6a\x76\xe8\x86\x90\x5b\xd4\x63\x6b\xf2\x9a\xa7\xc6\x97\x1f\x98\x4a\xc5\x01\xe4\xeb\xed\x66\x29\x51\xcb\x14\x5c\xab\x94\xa6\x9a\xa5\x01\x83\x22\xc7\xb0\xbe\x02\x32\x0b\xbd\x9d\x03\xdd\xcf\x56\xda\x9c\x9b\xc6\x1d\x5f\x82\x0c\xb3\xd0\x9e\x43\x51\x1c\x3f\x1b\x0a\x19\xd5\xd0\xe0\x74\x3f\xe6\xb3\x51\x18\x42\x0d\x31\x38\xae\xfa\x0d\x60\x51\xf5\xf6\xcc\x68\x76\xfc\xb5\x61\xe9\xea\xe0\x22\x0d\xc1\x58\x04\x35\x70\xa3\x58\xfe\x3a\xb1\x74\xa5\xc0\x93\x4d\xb3\xac\x61\xa6\xc5\xc7\x71\x44\x73\x5a\x28\xb0\x36\x09\xbb\x4b\x85\xf3\x42\xb5\x39\xa6\x16\xd8\xd9\x52\xf2\xdb\xa7\xef\x1e\x7f\xa5\x8f\x7f\x6e\x9f\xbb\x2f\xef\x1e\xbf\xdd\x3e\xff\xef\xad\xc5\xe5\x57\xfe\x9d\x1c\x70\x9b\xe4\xa0\x86\x4a\x49\x5f\xcf\x4e\x30\x0d\xa5\x63\x78\xc0\xdf\xa9\xb1\x6c\x62\xb1\x77\x68\xbe\x6b\x0f\xd1\xa2\x31\x35\x38\x3c\xaa\x02\x5a\x84\xd1\xbd\x36\x78\x8e\x9d\x05\x86\x9f\xf6\xde\xf8\x81\xf0\x3c\x92\x98\x8d\x78\xaf\xaa\x53\xd3\xab\x18\xc7\x67\xe5\xc8\x23\x4b\xeb\x9d\x22\x8f\xef\x98\xf7\x05\xef\x46\xe5\xdd\x74\x44\x88\xb6\x60\xde\xc7\x7d\xfe\xc6\xbc\xfb\xbd\xf8\x8a\xb4\x7b\xbe\xa2\x7e\xda\x8a\xfd\xd6\x7d\xbd\x62\x54\x01\xd2\xd1\xff\xb3\x15\xbd\x5e\x97\xa2\x05\xa3\x0a\x54\x18\xdc\x0e\x55\x30\x60\x4b\x4c\x9a\x30\xaa\x4f\x1e\x66\xe2\x1e\x8a\x6a\x1d\xe7\xc3\xb7\xdc\x3b\x29\x48\xa9\x86\x9d\x90\xaf\xeb\xa0\xe3\x7b\xc6\xf2\x4d\xc3\xc5\xf3\x2f\x39\xcb\xae\x39\x08\x0e\x76\xd5\xb9\xe0\xb2\xe3\x22\x22\xd3\x69\x12\x71\xe0\x0f\x22\xf6\x7b\xbd\x0a\xa7\xc6\x3b\xfa\x53\x1d\xd6\xc6\x98\x43\x99\x6a\x23\x33\xc9\x9d\xf4\x1b\x49\x71\xe7\xbc\x43\xb1\x82\xb1\x74\x72\xf8\x50\x76\x4c\x29\xe7\x42\x53\xdc\x6c\x37\x1e\x41\x90\x3d\xcb\x32\xe0\x7f\xdf\xd2\x84\xce\x0e\xec\x95\x1e\xde\x10\xff\xfd\xb3\x63\xf5\x26\xd7\x5d\xb3\x55\x18\xfd\x3e\x27\xa9\xb3\x71\x61\x31\xa2\xcd\x6c\xda\xa0\xe9\x7b\xd1\x65\xb7\x23\x47\x21\x66\xbb\x6d\xbd\x63\xff\xf2\x13\x79\x8a\x10\x80\xa5\xab\x23\x5a\xfd\x7e\xeb\x59\xb9\xf8\x3b\x99\x31\xcd\x8f\xdd\x19\x29\xfc\x6e\x25\x0e\x70\xc7\x6d\xdf\xf0\xa9\x8c\x6b\xd8\x81\xff\xe6\x93\xe4\x85\xa0\x6e\x81\x98\x5a\xaf\x65\xd3\xe0\xfc\x27\x05\x53\x08\x7f\x49\x2b\x9f\xd8\x74\x20\x8f\xda\xbc\x2c\xf2\xa8\x0a\x0a\xbf\x52\x07\x7a\x72\xf8\xc6\xae\xa9\xf3\x3b\x23\xab\x1c\x56\x6d\x30\x6a\x2f\xa4\xbe\x09\xda\x7d\x46\x8f\x0b\xd4\x02\x3f\x6c\xd7\x73\x2a\x97\xa2\xdc\x4e\xf6\x10\x1c\xbd\xeb\x21\x64\x33\xd1\x29\x82\x4a\x57\x6f\xd5\x07\x5a\xd4\xa0\xc2\xd8\xf6\xf1\x0e\x1e\xf2\xb9\xa3\x3e\x77\xe0\xa7\x4a\xd2\xc1\x46\xbd\x9d\xf1\x19\xf1\x7c\xb2\x54\x57\x55\xd9\x84\x7c\x45\x95\xd1\x8c\x09\xa1\x49\x80\xce\x83\xae\x89\x3c\x44\x8b\x6d\x38\x45\x01\xdd\xc3\xdb\x77\xd7\xf5\x69\xa7\x83\xaa\x6c\xf0\x91\x53\x17\xec\x89\x16\x84\x82\x54\x82\xbe\x01\xe0\x1f\x5b\x77\x2d\x80\xb2\x56\x7a\x0b\x47\x33\x7d\xae\x07\xf9\xc2\x78\x76\x69\xa2\x42\x03\xd8\xfc\x2f\xdc\xe1\x35\xad\x8d\xc9\xa5\x93\x0a\x8e\x1a\xb8\x9a\x0e\xeb\x75\x8d\x0a\x75\xe4\xca\xf2\x3e\x37\xfa\xaf\xd1\x72\x65\x0b\xcb\xbb\x64\x13\xc0\xfd\xce\x12\x96\xa6\xc7\x5b\xa4\x19\xdf\x16\xbd\x89\x33\x20\x63\x41\xa3\x98\x71\x56\x36\x55\xf1\x2e\x0c\x4f\x8f\x6b\xc2\x0b\x0e\x5b\x91\xaf\x54\x38\xd6\x63\x4b\xaf\x71\x8c\xf1\x0f\x1d\x8e\x95\xd8\x08\x11\x08\x35\xe5\x56\xe1\x43\x14\x6c\xd0\xce\xc2\x1e\xc5\xf1\x29\x3a\x45\x7f\x0d\x00\x24\x89\x8d\x82\x25\x24\x00\x00")
 
 func schemasManifestJsonBytes() ([]byte, error) {
 	return bindataRead(
@@ -83,8 +83,8 @@ func schemasManifestJson() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "schemas/manifest.json", size: 3932, mode: os.FileMode(0644), modTime: time.Unix(1683295929, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf6, 0xe6, 0xda, 0xea, 0x5c, 0x5d, 0x2a, 0x5, 0xd9, 0x1e, 0x65, 0x0, 0xcf, 0x41, 0xa3, 0xfc, 0x8, 0x24, 0x3c, 0x9e, 0x36, 0xa8, 0x17, 0xd, 0x18, 0x72, 0xa6, 0x5e, 0xd4, 0x30, 0xa8, 0x94}}
+	info := bindataFileInfo{name: "schemas/manifest.json", size: 9253, mode: os.FileMode(0644), modTime: time.Unix(1792310577, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x46, 0x7, 0xb0, 0x24, 0x56, 0x96, 0xb4, 0x8, 0xc3, 0xbb, 0x56, 0x89, 0x9e, 0x4d, 0x8c, 0x6b, 0xa1, 0x9c, 0xcb, 0x76, 0xd5, 0xab, 0x39, 0xe9, 0x31, 0x42, 0x3e, 0x53, 0x57, 0xfc, 0xee, 0xc1}}
 	return a, nil
 }
 
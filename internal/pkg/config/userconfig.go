@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	yaml2 "gopkg.in/yaml.v2"
+)
+
+// UserConfig defines the structure of the per-user config file
+type UserConfig struct {
+	Defaults map[string]string `yaml:"defaults,omitempty"`
+	Path     string            `yaml:"-"`
+}
+
+// UserConfigPath returns the path of the user config file for a cli ($XDG_CONFIG_HOME/<name>/config.yaml or ~/.config/<name>/config.yaml)
+func UserConfigPath(name string) string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, name, "config.yaml")
+}
+
+// LoadUserConfig loads the user config file of a cli, returns nil when the file does not exist
+func LoadUserConfig(name string) (*UserConfig, error) {
+	path := UserConfigPath(name)
+	if path == "" {
+		return nil, nil
+	}
+
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read user config file (path=%s). %v", path, err)
+	}
+
+	uc := UserConfig{}
+	err = yaml2.Unmarshal(bs, &uc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse user config file (path=%s). %v", path, err)
+	}
+	uc.Path = path
+
+	return &uc, nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/franela/goblin"
+)
+
+func TestUserConfig(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("LoadUserConfig", func() {
+		g.BeforeEach(func() {
+			wd, _ := os.Getwd()
+			os.Setenv("XDG_CONFIG_HOME", filepath.Join(wd, "test/data/userconfig"))
+		})
+
+		g.AfterEach(func() {
+			os.Unsetenv("XDG_CONFIG_HOME")
+		})
+
+		g.It("returns user config when file is found", func() {
+			uc, err := LoadUserConfig("centry")
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(uc != nil).IsTrue("expected user config")
+			g.Assert(filepath.Base(filepath.Dir(uc.Path))).Equal("centry")
+			g.Assert(uc.Defaults["level"]).Equal("user")
+			g.Assert(uc.Defaults["context"]).Equal("production")
+		})
+
+		g.It("returns nil when file does not exist", func() {
+			uc, err := LoadUserConfig("missing")
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(uc == nil).IsTrue("expected no user config")
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// CentryVersion is the version of centry, set at build time (-ldflags "-X github.com/kristofferahl/go-centry/internal/pkg/config.CentryVersion=<version>")
+var CentryVersion = "dev"
+
+// validateCentryVersion ensures the version of centry satisfies the version constraint of the manifest.
+// Development builds (versions that are not semantic versions) are not checked.
+func validateCentryVersion(m *Manifest, path string) error {
+	if m.Config.CentryVersion == "" {
+		return nil
+	}
+
+	c, err := semver.NewConstraint(m.Config.CentryVersion)
+	if err != nil {
+		return fmt.Errorf("invalid centry version constraint (centryVersion=%s path=%s). %v", m.Config.CentryVersion, path, err)
+	}
+
+	v, err := semver.NewVersion(CentryVersion)
+	if err != nil {
+		return nil
+	}
+
+	if !c.Check(v) {
+		return fmt.Errorf("the manifest requires a different version of centry (centryVersion=%s version=%s path=%s). Install a version of centry satisfying the constraint (https://github.com/kristofferahl/go-centry/releases)", m.Config.CentryVersion, v, path)
+	}
+
+	return nil
+}
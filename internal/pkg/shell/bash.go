@@ -2,11 +2,11 @@ package shell
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -39,9 +39,12 @@ func (bash *Bash) Run(io io.InputOutput, args []string) error {
 
 // BashScript encapsulates operations on the script file containing commands
 type BashScript struct {
-	BasePath string
-	Path     string
-	Log      *logrus.Entry
+	BasePath     string
+	Path         string
+	Dependencies []string
+	Discovery    config.FunctionDiscovery
+	Cache        *FunctionCache
+	Log          *logrus.Entry
 }
 
 // Language returns the name of the script language
@@ -66,6 +69,10 @@ func (s *BashScript) FullPath() string {
 
 // FunctionNames returns functions in declared in the script
 func (s *BashScript) FunctionNames() ([]string, error) {
+	if s.Discovery == config.FunctionDiscoveryStatic {
+		return s.staticFunctionNames()
+	}
+
 	callArgs := []string{"-c", fmt.Sprintf("set -e; source %s; declare -F", s.FullPath())}
 
 	io, buf := io.BufferedCombined()
@@ -88,8 +95,55 @@ func (s *BashScript) FunctionNames() ([]string, error) {
 	return functions, nil
 }
 
+// staticFunctionNames returns functions declared in the script by parsing it, without executing it
+func (s *BashScript) staticFunctionNames() ([]string, error) {
+	parsed, err := ParseBashScript(s.FullPath())
+	if err != nil {
+		return nil, err
+	}
+
+	return parsedFunctionNames(parsed), nil
+}
+
+// parsedFunctionNames returns the unique names of the functions of a parsed script
+func parsedFunctionNames(parsed *ParsedScript) []string {
+	functions := []string{}
+	seen := make(map[string]bool)
+	for _, fn := range parsed.Functions {
+		if !seen[fn.Name] {
+			seen[fn.Name] = true
+			functions = append(functions, fn.Name)
+		}
+	}
+
+	// Sorted by name like the output of declare -F
+	sort.Strings(functions)
+
+	return functions
+}
+
+// CheckSyntax reads the script file and reports syntax errors without executing it
+func (s *BashScript) CheckSyntax() error {
+	io, buf := io.BufferedCombined()
+
+	err := NewBash().Run(io, []string{"-n", s.FullPath()})
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(buf.String()))
+	}
+
+	return nil
+}
+
 // FunctionAnnotations returns function annotations declared in the script file
 func (s *BashScript) FunctionAnnotations() ([]*config.Annotation, error) {
+	if s.Discovery == config.FunctionDiscoveryStatic {
+		parsed, err := ParseBashScript(s.FullPath())
+		if err != nil {
+			return nil, err
+		}
+		return parsed.Annotations, nil
+	}
+
 	annotations := make([]*config.Annotation, 0)
 
 	file, err := os.Open(s.FullPath())
@@ -98,14 +152,17 @@ func (s *BashScript) FunctionAnnotations() ([]*config.Annotation, error) {
 	}
 	defer file.Close()
 
+	line := 0
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
+		line++
 		t := scanner.Text()
 		if strings.HasPrefix(t, "#") {
 			a, err := config.ParseAnnotation(strings.TrimLeft(t, "#"))
 			if err != nil {
 				s.Log.Debug(err.Error())
 			} else if a != nil {
+				a.Line = line
 				annotations = append(annotations, a)
 			}
 		}
@@ -118,16 +175,39 @@ func (s *BashScript) FunctionAnnotations() ([]*config.Annotation, error) {
 	return annotations, nil
 }
 
-// Functions returns the command functions
+// Functions returns the command functions, using the function cache when available
 func (s *BashScript) Functions() ([]*Function, error) {
-	funcs := make([]*Function, 0)
+	if s.Cache == nil {
+		return s.parseFunctions()
+	}
 
-	fnames, err := s.FunctionNames()
+	key, err := s.Cache.Key(s.FullPath(), s.Dependencies, s.Discovery)
+	if err != nil {
+		return s.parseFunctions()
+	}
+
+	if funcs, ok := s.Cache.Get(s.FullPath(), key); ok {
+		s.Log.Debugf("loaded functions from cache")
+		return funcs, nil
+	}
+
+	funcs, err := s.parseFunctions()
 	if err != nil {
 		return nil, err
 	}
 
-	annotations, err := s.FunctionAnnotations()
+	if err := s.Cache.Set(s.FullPath(), key, funcs); err != nil {
+		s.Log.Debugf("failed to cache functions. %v", err)
+	}
+
+	return funcs, nil
+}
+
+// parseFunctions sources the script and builds the command functions from the function names and annotations
+func (s *BashScript) parseFunctions() ([]*Function, error) {
+	funcs := make([]*Function, 0)
+
+	fnames, annotations, err := s.functionNamesAndAnnotations()
 	if err != nil {
 		return nil, err
 	}
@@ -143,8 +223,6 @@ func (s *BashScript) Functions() ([]*Function, error) {
 			Options: cmd.NewOptionsSet(fname),
 		}
 
-		options := make(map[string]*cmd.Option, 0)
-
 		for _, a := range annotations {
 			cmdName := a.NamespaceValues["cmd"]
 			if cmdName == "" || cmdName != f.Name {
@@ -158,73 +236,43 @@ func (s *BashScript) Functions() ([]*Function, error) {
 			}).Debugf("handling annotation")
 
 			switch a.Namespace {
-			case config.CommandAnnotationCmdOptionNamespace:
-				name := a.NamespaceValues["option"]
-				if name == "" {
-					continue
-				}
-				if options[name] == nil {
-					options[name] = &cmd.Option{Type: cmd.StringOption, Name: name}
-				}
-				switch a.Key {
-				case "type":
-					options[name].Type = cmd.StringToOptionType(a.Value)
-				case "short":
-					options[name].Short = a.Value
-				case "envName":
-					options[name].EnvName = a.Value
-				case "default":
-					options[name].Default = a.Value
-				case "required":
-					required, err := strconv.ParseBool(a.Value)
-					if err == nil {
-						options[name].Required = required
-					}
-				case "description":
-					options[name].Description = a.Value
-				case "hidden":
-					hidden, err := strconv.ParseBool(a.Value)
-					if err == nil {
-						options[name].Hidden = hidden
-					}
-				case "values":
-					values := make([]cmd.OptionValue, 0)
-					if err := json.Unmarshal([]byte(a.Value), &values); err != nil {
-						s.Log.WithFields(logrus.Fields{
-							"option": name,
-							"json":   a.Value,
-						}).Warn("error parsing json values, ", err.Error())
-					}
-					options[name].Values = values
-				}
 			case config.CommandAnnotationCmdNamespace:
 				switch a.Key {
 				case "description":
 					f.Description = a.Value
 				case "help":
 					f.Help = a.Value
+				case "category":
+					f.Category = a.Value
 				case "hidden":
 					hidden, err := strconv.ParseBool(a.Value)
 					if err == nil {
 						f.Hidden = hidden
 					}
+				case config.CommandAnnotationAliasesKey:
+					f.Aliases = config.ParseAnnotationList(a.Value)
+				case config.CommandAnnotationRequiresKey:
+					requires, err := config.ParseRequirements(a.Value)
+					if err != nil {
+						s.Log.WithFields(logrus.Fields{
+							"func": f.Name,
+						}).Warn("error parsing requirements, ", err.Error())
+					}
+					f.Requires = requires
 				}
 			}
 		}
 
-		for _, v := range options {
-			if err := v.Validate(); err != nil {
-				s.Log.WithFields(logrus.Fields{
-					"option": v.Name,
-					"type":   v.Type,
-				}).Warn(err.Error())
-			} else {
-				if err := f.Options.Add(v); err != nil {
-					s.Log.WithFields(logrus.Fields{
-						"option": v.Name,
-						"type":   v.Type,
-					}).Warn(err.Error())
-				}
+		for _, ao := range AnnotatedOptions(f.Name, annotations) {
+			log := s.Log.WithFields(logrus.Fields{
+				"option": ao.Option.Name,
+				"type":   ao.Option.Type,
+			})
+			for _, err := range ao.Errors {
+				log.Warn(err.Error())
+			}
+			if err := f.Options.Add(ao.Option); err != nil {
+				log.Warn(err.Error())
 			}
 		}
 
@@ -234,6 +282,29 @@ func (s *BashScript) Functions() ([]*Function, error) {
 	return funcs, nil
 }
 
+// functionNamesAndAnnotations returns the function names and annotations of the script, static discovery parses the script once for both
+func (s *BashScript) functionNamesAndAnnotations() ([]string, []*config.Annotation, error) {
+	if s.Discovery == config.FunctionDiscoveryStatic {
+		parsed, err := ParseBashScript(s.FullPath())
+		if err != nil {
+			return nil, nil, err
+		}
+		return parsedFunctionNames(parsed), parsed.Annotations, nil
+	}
+
+	fnames, err := s.FunctionNames()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	annotations, err := s.FunctionAnnotations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fnames, annotations, nil
+}
+
 // FunctionNamespace returns a namespaced function name
 func (s *BashScript) FunctionNamespace(name string) string {
 	return fmt.Sprintf("%s%s", name, s.FunctionNamespaceSplitChar())
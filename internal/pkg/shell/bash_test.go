@@ -0,0 +1,90 @@
+package shell
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/franela/goblin"
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+func TestBashScript(t *testing.T) {
+	g := Goblin(t)
+
+	script := func(path string, discovery config.FunctionDiscovery) *BashScript {
+		return &BashScript{
+			BasePath:  "test/data",
+			Path:      path,
+			Discovery: discovery,
+			Log:       logrus.NewEntry(logrus.New()),
+		}
+	}
+
+	g.Describe("static function discovery", func() {
+		files, _ := filepath.Glob("test/data/commands/*.sh")
+		globs, _ := filepath.Glob("test/data/commands/glob/*.sh")
+		files = append(files, globs...)
+
+		for _, f := range files {
+			path, _ := filepath.Rel("test/data", f)
+
+			g.It("finds the same functions as source discovery ("+path+")", func() {
+				expected, err := script(path, config.FunctionDiscoverySource).FunctionNames()
+				g.Assert(err == nil).IsTrue("expected no error", err)
+
+				actual, err := script(path, config.FunctionDiscoveryStatic).FunctionNames()
+				g.Assert(err == nil).IsTrue("expected no error", err)
+				g.Assert(actual).Equal(expected)
+			})
+
+			g.It("finds the same annotations as source discovery ("+path+")", func() {
+				expected, err := script(path, config.FunctionDiscoverySource).FunctionAnnotations()
+				g.Assert(err == nil).IsTrue("expected no error", err)
+
+				actual, err := script(path, config.FunctionDiscoveryStatic).FunctionAnnotations()
+				g.Assert(err == nil).IsTrue("expected no error", err)
+				g.Assert(actual).Equal(expected)
+			})
+		}
+
+		g.It("does not execute the script", func() {
+			_, err := script("shell/static_test.sh", config.FunctionDiscoverySource).FunctionNames()
+			g.Assert(err != nil).IsTrue("expected source discovery to fail")
+
+			names, err := script("shell/static_test.sh", config.FunctionDiscoveryStatic).FunctionNames()
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(names).Equal([]string{"statictest:conditional", "statictest:first", "statictest:second", "statictest:third"})
+		})
+
+		g.It("returns error when the script can not be parsed", func() {
+			_, err := script("commands/validate/validate_syntax_error.sh", config.FunctionDiscoveryStatic).FunctionNames()
+			g.Assert(err != nil).IsTrue("expected error")
+		})
+	})
+
+	g.Describe("ParseBashScript", func() {
+		g.It("returns functions declared at the top level of the script", func() {
+			parsed, err := ParseBashScript("test/data/shell/static_test.sh")
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(len(parsed.Functions)).Equal(4)
+
+			first := parsed.Functions[0]
+			g.Assert(first.Name).Equal("statictest:first")
+			g.Assert(first.Line).Equal(8)
+
+			g.Assert(parsed.Functions[1].Name).Equal("statictest:second")
+			g.Assert(parsed.Functions[2].Name).Equal("statictest:third")
+			g.Assert(parsed.Functions[3].Name).Equal("statictest:conditional")
+		})
+
+		g.It("returns annotations from comments only", func() {
+			parsed, err := ParseBashScript("test/data/shell/static_test.sh")
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(len(parsed.Annotations)).Equal(3)
+			for _, a := range parsed.Annotations {
+				g.Assert(a.Value != "Not an annotation").IsTrue("expected heredoc content to be ignored")
+			}
+		})
+	})
+}
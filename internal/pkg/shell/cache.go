@@ -0,0 +1,246 @@
+package shell
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
+)
+
+// functionCacheFormat is the version of the cache entry format and the way functions are parsed, bump it when either changes
+const functionCacheFormat = 2
+
+// FunctionCache is a disk cache of the functions parsed from script files.
+// Entries are stored per script and keyed by a hash of the script and the files it depends on.
+type FunctionCache struct {
+	Dir string
+}
+
+type functionCacheEntry struct {
+	Key       string           `json:"key"`
+	Functions []cachedFunction `json:"functions"`
+}
+
+type cachedFunction struct {
+	Name        string               `json:"name"`
+	Aliases     []string             `json:"aliases,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Help        string               `json:"help,omitempty"`
+	Category    string               `json:"category,omitempty"`
+	Hidden      bool                 `json:"hidden,omitempty"`
+	Requires    []config.Requirement `json:"requires,omitempty"`
+	Options     []cachedOption       `json:"options,omitempty"`
+}
+
+type cachedOption struct {
+	Type        cmd.OptionType    `json:"type"`
+	Name        string            `json:"name"`
+	Short       string            `json:"short,omitempty"`
+	EnvName     string            `json:"envName,omitempty"`
+	FromEnv     string            `json:"fromEnv,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Required    bool              `json:"required,omitempty"`
+	Hidden      bool              `json:"hidden,omitempty"`
+	Secret      bool              `json:"secret,omitempty"`
+	Values      []cmd.OptionValue `json:"values,omitempty"`
+	Default     *string           `json:"default,omitempty"`
+	MustExist   bool              `json:"mustExist,omitempty"`
+	Kind        string            `json:"kind,omitempty"`
+	Extensions  []string          `json:"extensions,omitempty"`
+	Rules       *cmd.OptionRules  `json:"rules,omitempty"`
+}
+
+// DefaultFunctionCacheDir returns the default directory of the function cache ($XDG_CACHE_HOME/centry/functions or the user cache directory of the OS)
+func DefaultFunctionCacheDir() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "centry", "functions"), nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "centry", "functions"), nil
+}
+
+// NewFunctionCache creates a new function cache
+func NewFunctionCache(dir string) *FunctionCache {
+	return &FunctionCache{
+		Dir: dir,
+	}
+}
+
+// Key returns the cache key for a script, based on the version of centry and the cache format, how functions are discovered and the content of the script and the files it depends on
+func (c *FunctionCache) Key(script string, dependencies []string, discovery config.FunctionDiscovery) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "centry=%s\n", config.CentryVersion)
+	fmt.Fprintf(h, "format=%d\n", functionCacheFormat)
+	fmt.Fprintf(h, "discovery=%s\n", discovery)
+
+	for i, f := range append([]string{script}, dependencies...) {
+		bs, err := ioutil.ReadFile(f)
+		if err != nil {
+			// Only the script itself is required to exist, missing dependencies fail when the command is executed
+			if i == 0 {
+				return "", err
+			}
+			bs = []byte{}
+		}
+
+		sum := sha256.Sum256(bs)
+		fmt.Fprintf(h, "%s=%s\n", f, hex.EncodeToString(sum[:]))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Get returns the cached functions of a script, ok is false when the script is not cached or the key does not match
+func (c *FunctionCache) Get(script string, key string) (funcs []*Function, ok bool) {
+	bs, err := ioutil.ReadFile(c.entryPath(script))
+	if err != nil {
+		return nil, false
+	}
+
+	entry := functionCacheEntry{}
+	if err := json.Unmarshal(bs, &entry); err != nil || entry.Key != key {
+		return nil, false
+	}
+
+	funcs = make([]*Function, 0)
+	for _, cf := range entry.Functions {
+		f := &Function{
+			Name:        cf.Name,
+			Aliases:     cf.Aliases,
+			Description: cf.Description,
+			Help:        cf.Help,
+			Category:    cf.Category,
+			Hidden:      cf.Hidden,
+			Requires:    cf.Requires,
+			Options:     cmd.NewOptionsSet(cf.Name),
+		}
+
+		for _, co := range cf.Options {
+			o := &cmd.Option{
+				Type:        co.Type,
+				Name:        co.Name,
+				Short:       co.Short,
+				EnvName:     co.EnvName,
+				FromEnv:     co.FromEnv,
+				Description: co.Description,
+				Required:    co.Required,
+				Hidden:      co.Hidden,
+				Secret:      co.Secret,
+				Values:      co.Values,
+				MustExist:   co.MustExist,
+				Kind:        co.Kind,
+				Extensions:  co.Extensions,
+			}
+			if co.Rules != nil {
+				o.Rules = *co.Rules
+			}
+			if co.Default != nil {
+				o.Default = *co.Default
+			}
+
+			if err := f.Options.Add(o); err != nil {
+				return nil, false
+			}
+		}
+
+		funcs = append(funcs, f)
+	}
+
+	return funcs, true
+}
+
+// Set stores the functions of a script in the cache
+func (c *FunctionCache) Set(script string, key string, funcs []*Function) error {
+	entry := functionCacheEntry{
+		Key:       key,
+		Functions: make([]cachedFunction, 0),
+	}
+
+	for _, f := range funcs {
+		cf := cachedFunction{
+			Name:        f.Name,
+			Aliases:     f.Aliases,
+			Description: f.Description,
+			Help:        f.Help,
+			Category:    f.Category,
+			Hidden:      f.Hidden,
+			Requires:    f.Requires,
+			Options:     make([]cachedOption, 0),
+		}
+
+		for _, o := range f.Options.Sorted() {
+			co := cachedOption{
+				Type:        o.Type,
+				Name:        o.Name,
+				Short:       o.Short,
+				EnvName:     o.EnvName,
+				FromEnv:     o.FromEnv,
+				Description: o.Description,
+				Required:    o.Required,
+				Hidden:      o.Hidden,
+				Secret:      o.Secret,
+				Values:      o.Values,
+				MustExist:   o.MustExist,
+				Kind:        o.Kind,
+				Extensions:  o.Extensions,
+			}
+			if !o.Rules.IsEmpty() {
+				rules := o.Rules
+				co.Rules = &rules
+			}
+			if o.Default != nil {
+				def := fmt.Sprint(o.Default)
+				if values, ok := o.Default.([]string); ok {
+					def = strings.Join(values, ",")
+				}
+				co.Default = &def
+			}
+			cf.Options = append(cf.Options, co)
+		}
+
+		entry.Functions = append(entry.Functions, cf)
+	}
+
+	bs, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, making concurrent invocations safe
+	tmp, err := ioutil.TempFile(c.Dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bs); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.entryPath(script))
+}
+
+// entryPath returns the path of the cache entry of a script, a single entry is kept per script
+func (c *FunctionCache) entryPath(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,68 @@
+package shell
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/franela/goblin"
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+)
+
+func TestFunctionCache(t *testing.T) {
+	g := Goblin(t)
+
+	g.Describe("DefaultFunctionCacheDir", func() {
+		g.It("returns directory in XDG_CACHE_HOME when set", func() {
+			xdg, ok := os.LookupEnv("XDG_CACHE_HOME")
+			defer func() {
+				if ok {
+					os.Setenv("XDG_CACHE_HOME", xdg)
+				} else {
+					os.Unsetenv("XDG_CACHE_HOME")
+				}
+			}()
+
+			os.Setenv("XDG_CACHE_HOME", "/tmp/xdg-cache")
+			dir, err := DefaultFunctionCacheDir()
+			g.Assert(err == nil).IsTrue("expected no error", err)
+			g.Assert(dir).Equal(filepath.Join("/tmp/xdg-cache", "centry", "functions"))
+		})
+	})
+
+	g.Describe("Set and Get", func() {
+		var dir string
+
+		g.Before(func() {
+			dir, _ = ioutil.TempDir("", "centry-cache")
+		})
+
+		g.After(func() {
+			os.RemoveAll(dir)
+		})
+
+		g.It("stores option rules using camel cased keys and omits empty rules", func() {
+			min := 2
+			options := cmd.NewOptionsSet("cachetest")
+			options.Add(&cmd.Option{Type: cmd.StringOption, Name: "name", Rules: cmd.OptionRules{Pattern: "^[a-z]+$", MinLength: &min}})
+			options.Add(&cmd.Option{Type: cmd.StringOption, Name: "plain"})
+
+			c := NewFunctionCache(dir)
+			err := c.Set("cachetest.sh", "key", []*Function{{Name: "cachetest", Options: options}})
+			g.Assert(err == nil).IsTrue("expected no error", err)
+
+			bs, _ := ioutil.ReadFile(c.entryPath("cachetest.sh"))
+			g.Assert(strings.Contains(string(bs), `"rules":{"pattern":"^[a-z]+$","minLength":2}`)).IsTrue(string(bs))
+			g.Assert(strings.Count(string(bs), `"rules"`)).Equal(1)
+
+			funcs, ok := c.Get("cachetest.sh", "key")
+			g.Assert(ok).IsTrue("expected cache hit")
+			rules := funcs[0].Options.Sorted()[0].Rules
+			g.Assert(rules.Pattern).Equal("^[a-z]+$")
+			g.Assert(*rules.MinLength).Equal(2)
+			g.Assert(funcs[0].Options.Sorted()[1].Rules.IsEmpty()).IsTrue("expected no rules")
+		})
+	})
+}
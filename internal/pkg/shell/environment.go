@@ -16,6 +16,18 @@ const (
 
 	// EnvironmentVariableTypeInteger represents an integer environment variable
 	EnvironmentVariableTypeInteger EnvironmentVariableType = "integer"
+
+	// EnvironmentVariableTypeFloat represents a float environment variable
+	EnvironmentVariableTypeFloat EnvironmentVariableType = "float"
+
+	// EnvironmentVariableTypeDuration represents a duration environment variable
+	EnvironmentVariableTypeDuration EnvironmentVariableType = "duration"
+
+	// EnvironmentVariableTypeList represents a newline delimited list environment variable
+	EnvironmentVariableTypeList EnvironmentVariableType = "list"
+
+	// EnvironmentVariableTypeMap represents a newline delimited key=value environment variable
+	EnvironmentVariableTypeMap EnvironmentVariableType = "map"
 )
 
 // EnvironmentVariable represents an environment variable
@@ -23,6 +35,12 @@ type EnvironmentVariable struct {
 	Name  string
 	Value string
 	Type  EnvironmentVariableType
+
+	// Secret is true for the variables of secret options, their values are redacted when logged
+	Secret bool
+
+	// Values holds the individual values of list and map environment variables
+	Values []string
 }
 
 // IsString returns true if the environment variable is of type string
@@ -40,6 +58,26 @@ func (v EnvironmentVariable) IsInteger() bool {
 	return v.Type == EnvironmentVariableTypeInteger
 }
 
+// IsFloat returns true if the environment variable is of type float
+func (v EnvironmentVariable) IsFloat() bool {
+	return v.Type == EnvironmentVariableTypeFloat
+}
+
+// IsDuration returns true if the environment variable is of type duration
+func (v EnvironmentVariable) IsDuration() bool {
+	return v.Type == EnvironmentVariableTypeDuration
+}
+
+// IsList returns true if the environment variable is of type list
+func (v EnvironmentVariable) IsList() bool {
+	return v.Type == EnvironmentVariableTypeList
+}
+
+// IsMap returns true if the environment variable is of type map
+func (v EnvironmentVariable) IsMap() bool {
+	return v.Type == EnvironmentVariableTypeMap
+}
+
 // SortEnvironmentVariables sorts environment variables by name
 func SortEnvironmentVariables(vars []EnvironmentVariable) []EnvironmentVariable {
 	sort.Slice(vars, func(i, j int) bool {
@@ -0,0 +1,133 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/cmd"
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
+)
+
+// AnnotatedOption is an option of a function built from annotations
+type AnnotatedOption struct {
+	Option *cmd.Option
+	Line   int
+	Lines  map[string]int
+	Errors []*AnnotationError
+}
+
+// AnnotationError is an error caused by the value of an annotation
+type AnnotationError struct {
+	Line int
+	Err  error
+}
+
+func (e *AnnotationError) Error() string {
+	return e.Err.Error()
+}
+
+// AnnotatedOptions builds the options of a function from annotations, in the order they are first declared.
+// Values that can not be parsed are returned as errors of the option, the options themselves are not validated.
+func AnnotatedOptions(fname string, annotations []*config.Annotation) []*AnnotatedOption {
+	options := make([]*AnnotatedOption, 0)
+	byName := make(map[string]*AnnotatedOption)
+
+	for _, a := range annotations {
+		if a.Namespace != config.CommandAnnotationCmdOptionNamespace || a.NamespaceValues["cmd"] != fname {
+			continue
+		}
+
+		name := a.NamespaceValues["option"]
+		if name == "" {
+			continue
+		}
+
+		ao := byName[name]
+		if ao == nil {
+			ao = &AnnotatedOption{
+				Option: &cmd.Option{Type: cmd.StringOption, Name: name},
+				Line:   a.Line,
+				Lines:  make(map[string]int),
+				Errors: make([]*AnnotationError, 0),
+			}
+			byName[name] = ao
+			options = append(options, ao)
+		}
+		ao.Lines[a.Key] = a.Line
+
+		if err := applyOptionAnnotation(ao.Option, a); err != nil {
+			ao.Errors = append(ao.Errors, &AnnotationError{Line: a.Line, Err: err})
+		}
+	}
+
+	return options
+}
+
+func applyOptionAnnotation(o *cmd.Option, a *config.Annotation) error {
+	parseBool := func(target *bool) error {
+		v, err := strconv.ParseBool(a.Value)
+		if err != nil {
+			return fmt.Errorf("invalid %s for option \"%s\", must be a boolean (value=%s)", a.Key, o.Name, a.Value)
+		}
+		*target = v
+		return nil
+	}
+
+	parseInt := func(target **int) error {
+		v, err := strconv.Atoi(a.Value)
+		if err != nil {
+			return fmt.Errorf("invalid %s for option \"%s\", must be an integer (value=%s)", a.Key, o.Name, a.Value)
+		}
+		*target = &v
+		return nil
+	}
+
+	switch a.Key {
+	case "type":
+		o.Type = cmd.StringToOptionType(a.Value)
+	case "short":
+		o.Short = a.Value
+	case "envName":
+		o.EnvName = a.Value
+	case "fromEnv":
+		o.FromEnv = a.Value
+	case "default":
+		o.Default = a.Value
+	case "required":
+		return parseBool(&o.Required)
+	case "secret":
+		return parseBool(&o.Secret)
+	case "description":
+		o.Description = a.Value
+	case "hidden":
+		return parseBool(&o.Hidden)
+	case "values":
+		values := make([]cmd.OptionValue, 0)
+		err := json.Unmarshal([]byte(a.Value), &values)
+		o.Values = values
+		if err != nil {
+			return fmt.Errorf("invalid json for option values (option=%s). %v", o.Name, err)
+		}
+	case "mustExist":
+		return parseBool(&o.MustExist)
+	case "kind":
+		o.Kind = a.Value
+	case "extensions":
+		o.Extensions = config.ParseAnnotationList(a.Value)
+	case "pattern":
+		o.Rules.Pattern = a.Value
+	case "min":
+		return parseInt(&o.Rules.Min)
+	case "max":
+		return parseInt(&o.Rules.Max)
+	case "minLength":
+		return parseInt(&o.Rules.MinLength)
+	case "maxLength":
+		return parseInt(&o.Rules.MaxLength)
+	case "oneOf":
+		o.Rules.OneOf = config.ParseAnnotationList(a.Value)
+	}
+
+	return nil
+}
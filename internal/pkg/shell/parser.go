@@ -0,0 +1,92 @@
+package shell
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kristofferahl/go-centry/internal/pkg/config"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// ParsedScript holds the function definitions and annotations found by statically parsing a script
+type ParsedScript struct {
+	Functions   []*ParsedFunction
+	Annotations []*config.Annotation
+}
+
+// ParsedFunction defines a function found by statically parsing a script
+type ParsedFunction struct {
+	Name string
+	Line int
+}
+
+// ParseBashScript parses a bash script without executing it, finding function definitions (name(), function name) and annotations.
+// Functions declared inside the body of other functions or in sourced files are not included.
+func ParseBashScript(path string) (*ParsedScript, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	file, err := syntax.NewParser(syntax.KeepComments(true), syntax.Variant(syntax.LangBash)).Parse(f, path)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &ParsedScript{
+		Functions:   make([]*ParsedFunction, 0),
+		Annotations: make([]*config.Annotation, 0),
+	}
+
+	// Annotations may be declared anywhere in the script
+	syntax.Walk(file, func(node syntax.Node) bool {
+		if c, ok := node.(*syntax.Comment); ok {
+			if a := parseCommentAnnotation(c); a != nil {
+				parsed.Annotations = append(parsed.Annotations, a)
+			}
+		}
+		return true
+	})
+	sort.SliceStable(parsed.Annotations, func(i, j int) bool {
+		return parsed.Annotations[i].Line < parsed.Annotations[j].Line
+	})
+
+	// Functions declared inside the body of another function are only defined once it is called
+	syntax.Walk(file, func(node syntax.Node) bool {
+		stmt, ok := node.(*syntax.Stmt)
+		if !ok {
+			return true
+		}
+
+		fn, ok := stmt.Cmd.(*syntax.FuncDecl)
+		if !ok {
+			return true
+		}
+
+		parsed.Functions = append(parsed.Functions, &ParsedFunction{
+			Name: fn.Name.Value,
+			Line: int(fn.Pos().Line()),
+		})
+
+		return false
+	})
+
+	return parsed, nil
+}
+
+// parseCommentAnnotation returns the annotation of a comment, only comments starting at the beginning of a line are considered
+func parseCommentAnnotation(c *syntax.Comment) *config.Annotation {
+	if c.Hash.Col() != 1 {
+		return nil
+	}
+
+	a, err := config.ParseAnnotation(strings.TrimLeft(c.Text, "#"))
+	if err != nil || a == nil {
+		return nil
+	}
+	a.Line = int(c.Hash.Line())
+
+	return a
+}
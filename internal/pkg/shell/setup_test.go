@@ -0,0 +1,10 @@
+package shell
+
+import (
+	"os"
+)
+
+func init() {
+	// Esuring the workdir is the root of the repo
+	os.Chdir("../../../")
+}
@@ -1,6 +1,7 @@
 package shell
 
 import "github.com/kristofferahl/go-centry/internal/pkg/cmd"
+import "github.com/kristofferahl/go-centry/internal/pkg/config"
 import "github.com/kristofferahl/go-centry/internal/pkg/io"
 
 // Executable defines the interface of an executable program
@@ -11,9 +12,12 @@ type Executable interface {
 // Function defines a function
 type Function struct {
 	Name        string
+	Aliases     []string
 	Description string
 	Help        string
+	Category    string
 	Hidden      bool
+	Requires    []config.Requirement
 	Options     *cmd.OptionsSet
 }
 
@@ -24,6 +28,8 @@ type Script interface {
 	FullPath() string
 	RelativePath() string
 	Functions() (funcs []*Function, err error)
+	FunctionAnnotations() ([]*config.Annotation, error)
+	CheckSyntax() error
 	FunctionNamespace(name string) string
 	FunctionNamespaceSplitChar() string
 }
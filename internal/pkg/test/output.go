@@ -63,12 +63,6 @@ func (output *Output) capture(f func()) *OutputCapture {
 
 	f()
 
-	if capturedExitCode > 0 {
-		return &OutputCapture{
-			ExitCode: capturedExitCode,
-		}
-	}
-
 	wOut.Close()
 	wErr.Close()
 
@@ -79,7 +73,8 @@ func (output *Output) capture(f func()) *OutputCapture {
 	io.Copy(&stderrBuf, rErr)
 
 	return &OutputCapture{
-		Stdout: stdoutBuf.String(),
-		Stderr: stderrBuf.String(),
+		Stdout:   stdoutBuf.String(),
+		Stderr:   stderrBuf.String(),
+		ExitCode: capturedExitCode,
 	}
 }
@@ -0,0 +1,5 @@
+#!/usr/bin/env bash
+
+aliascollision() {
+  echo "collision ($*)"
+}
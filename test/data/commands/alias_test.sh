@@ -0,0 +1,18 @@
+#!/usr/bin/env bash
+
+# centry.cmd[aliastest:remove]/description=Removes things
+# centry.cmd[aliastest:remove]/aliases=rm, del
+aliastest:remove() {
+  echo "remove ($*)"
+}
+
+# centry.cmd[aliastest:group]/aliases=g
+aliastest:group:list() {
+  echo "group list ($*)"
+}
+
+# centry.cmd[aliastest:trash]/description=Trashes things
+# centry.cmd[aliastest:trash]/aliases=rm
+aliastest:trash() {
+  echo "trash ($*)"
+}
@@ -0,0 +1,18 @@
+#!/usr/bin/env bash
+
+# centry.cmd[categorytest:build]/description=Builds things
+# centry.cmd[categorytest:build]/category=Development
+categorytest:build() {
+  echo "build ($*)"
+}
+
+# centry.cmd[categorytest:deploy]/description=Deploys things
+# centry.cmd[categorytest:deploy]/category=Operations
+categorytest:deploy() {
+  echo "deploy ($*)"
+}
+
+# centry.cmd[categorytest:clean]/description=Cleans things
+categorytest:clean() {
+  echo "clean ($*)"
+}
@@ -0,0 +1,16 @@
+#!/usr/bin/env bash
+
+envtest() {
+  echo "GLOBAL_FILE=${GLOBAL_FILE:-}"
+  echo "GLOBAL_ENV=${GLOBAL_ENV:-}"
+  echo "COMMAND_FILE=${COMMAND_FILE:-}"
+  echo "COMMAND_ENV=${COMMAND_ENV:-}"
+  echo "OVERRIDDEN_BY_GLOBAL_ENV=${OVERRIDDEN_BY_GLOBAL_ENV:-}"
+  echo "OVERRIDDEN_BY_COMMAND_FILE=${OVERRIDDEN_BY_COMMAND_FILE:-}"
+  echo "OVERRIDDEN_BY_COMMAND_ENV=${OVERRIDDEN_BY_COMMAND_ENV:-}"
+  echo "QUOTED=${QUOTED:-}"
+}
+
+envmissing() {
+  echo "should not run"
+}
@@ -0,0 +1,7 @@
+#!/usr/bin/env bash
+
+# centry.cmd[fromenvtest].option[name]/required=true
+# centry.cmd[fromenvtest].option[name]/fromEnv=CENTRY_TEST_NAME
+fromenvtest() {
+  echo "context=${CONTEXT:-} level=${LEVEL:-} name=${NAME:-} token=${EXPLAIN_TOKEN:-}"
+}
@@ -0,0 +1,5 @@
+#!/usr/bin/env bash
+
+glob_one() {
+  echo "glob_one args ($*)"
+}
@@ -0,0 +1,12 @@
+#!/usr/bin/env bash
+
+# centry.cmd/name=globtwo
+
+# centry.cmd[globtwo]/description=Named by annotation
+globtwo() {
+  echo "globtwo args ($*)"
+}
+
+globtwo:sub() {
+  echo "globtwo:sub args ($*)"
+}
@@ -0,0 +1,17 @@
+#!/usr/bin/env bash
+
+manifestoptions:plain() {
+  echo "level=${LEVEL} name=${NAME} count=${COUNT}"
+}
+
+# centry.cmd[manifestoptions:annotated].option[level]/type=string
+# centry.cmd[manifestoptions:annotated].option[level]/default=annotation
+manifestoptions:annotated() {
+  echo "level=${LEVEL} name=${NAME} count=${COUNT}"
+}
+
+# centry.cmd[manifestoptions:override].option[level]/type=string
+# centry.cmd[manifestoptions:override].option[level]/default=annotation
+manifestoptions:override() {
+  echo "level=${LEVEL} name=${NAME} count=${COUNT}"
+}
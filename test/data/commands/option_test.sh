@@ -12,6 +12,66 @@ optiontest:noop() {
   return 0
 }
 
+# centry.cmd[optiontest:numbers].option[floatopt]/type=float
+# centry.cmd[optiontest:numbers].option[floatopt]/required=true
+# centry.cmd[optiontest:numbers].option[durationopt]/type=duration
+# centry.cmd[optiontest:numbers].option[durationopt]/required=true
+optiontest:numbers() {
+  echo "FLOATOPT=${FLOATOPT}"
+  echo "DURATIONOPT=${DURATIONOPT}"
+  echo "DURATIONOPT_SECONDS=${DURATIONOPT_SECONDS}"
+}
+
+# centry.cmd[optiontest:collections].option[listopt]/type=list
+# centry.cmd[optiontest:collections].option[listopt]/default=x,y
+# centry.cmd[optiontest:collections].option[mapopt]/type=map
+optiontest:collections() {
+  echo "LISTOPT_COUNT=${#LISTOPT_ARRAY[@]}"
+  for i in "${!LISTOPT_ARRAY[@]}"; do
+    echo "LISTOPT_${i}=${LISTOPT_ARRAY[$i]}"
+  done
+  for k in "${!MAPOPT_MAP[@]}"; do
+    echo "MAPOPT_${k}=${MAPOPT_MAP[$k]}"
+  done
+  echo "LISTOPT_LINES=$(echo "${LISTOPT}" | wc -l | tr -d ' ')"
+}
+
+# centry.cmd[optiontest:path].option[fileopt]/type=path
+# centry.cmd[optiontest:path].option[fileopt]/mustExist=true
+# centry.cmd[optiontest:path].option[fileopt]/kind=file
+# centry.cmd[optiontest:path].option[fileopt]/extensions=yaml,yml
+# centry.cmd[optiontest:path].option[diropt]/type=path
+# centry.cmd[optiontest:path].option[diropt]/kind=dir
+# centry.cmd[optiontest:path].option[diropt]/default=commands
+optiontest:path() {
+  echo "FILEOPT=${FILEOPT}"
+  echo "DIROPT=${DIROPT}"
+}
+
+# centry.cmd[optiontest:enum].option[format]/type=enum
+# centry.cmd[optiontest:enum].option[format]/short=f
+# centry.cmd[optiontest:enum].option[format]/default=table
+# centry.cmd[optiontest:enum].option[format]/description=Output format
+# centry.cmd[optiontest:enum].option[format]/values=[{"name":"json"},{"name":"yaml","value":"yml"},{"name":"table"}]
+optiontest:enum() {
+  echo "FORMAT=${FORMAT}"
+}
+
+# centry.cmd[optiontest:rules].option[port]/type=integer
+# centry.cmd[optiontest:rules].option[port]/min=1
+# centry.cmd[optiontest:rules].option[port]/max=65535
+# centry.cmd[optiontest:rules].option[name]/pattern=^[a-z]+$
+# centry.cmd[optiontest:rules].option[name]/minLength=2
+# centry.cmd[optiontest:rules].option[name]/maxLength=8
+# centry.cmd[optiontest:rules].option[env]/oneOf=dev,prod
+# centry.cmd[optiontest:rules].option[tag]/type=list
+# centry.cmd[optiontest:rules].option[tag]/pattern=^v[0-9]+$
+optiontest:rules() {
+  echo "PORT=${PORT}"
+  echo "NAME=${NAME}"
+  echo "ENV=${ENV}"
+}
+
 # centry.cmd[optiontest:required].option[stringopt]/required=true
 # centry.cmd[optiontest:required].option[boolopt]/type=bool
 # centry.cmd[optiontest:required].option[boolopt]/required=true
@@ -0,0 +1,16 @@
+#!/usr/bin/env bash
+
+# centry.cmd[requirestest:ok]/requires=bash >=3
+requirestest:ok() {
+  echo "ok ($*)"
+}
+
+# centry.cmd[requirestest:missing]/requires=bash, centry-missing-tool
+requirestest:missing() {
+  echo "missing ($*)"
+}
+
+# centry.cmd[requirestest:version]/requires=bash <2
+requirestest:version() {
+  echo "version ($*)"
+}
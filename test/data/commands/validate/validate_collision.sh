@@ -0,0 +1,5 @@
+#!/usr/bin/env bash
+
+validatetest:run() {
+  return 0
+}
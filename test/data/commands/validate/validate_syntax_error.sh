@@ -0,0 +1,6 @@
+#!/usr/bin/env bash
+
+syntaxerror() {
+  if true; then
+    return 0
+}
@@ -0,0 +1,22 @@
+#!/usr/bin/env bash
+
+# centry.cmd[validatetest:options].option[first]/short=f
+# centry.cmd[validatetest:options].option[second]/short=f
+# centry.cmd[validatetest:options].option[third]/envName=FIRST
+# centry.cmd[validatetest:options].option[fourth]/envName=GLOBALOPT
+# centry.cmd[validatetest:options].option[first]/unknown=true
+# centry.cmd[validatetest:options].option[fifth]/min=abc
+# centry.cmd[validatetest:options].option[sixth]/kind=socket
+validatetest:options() {
+  return 0
+}
+
+# centry.cmd[validatetest:missing]/description=Refers to a function that does not exist
+validatetest:run() {
+  return 0
+}
+
+# centry.cmd[validatetest:requires]/requires=bash ~>nope
+validatetest:requires() {
+  return 0
+}
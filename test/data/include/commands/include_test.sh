@@ -0,0 +1,5 @@
+#!/usr/bin/env bash
+
+includetest() {
+  echo "include args ($*)"
+}
@@ -0,0 +1,31 @@
+#!/usr/bin/env bash
+
+# Top level code is not executed when parsing statically
+exit 1
+
+# centry.cmd[statictest:first]/description=First function
+# centry.cmd[statictest:first]/hidden=false
+statictest:first() {
+  echo "first"
+}
+
+# centry.cmd[statictest:second]/description=Declared using the function keyword
+function statictest:second {
+  echo "second"
+}
+
+function statictest:third() {
+  # centry.cmd[statictest:third]/help=Indented annotations are ignored
+  statictest:nested() {
+    echo "nested"
+  }
+
+  cat <<EOT
+# centry.cmd[statictest:third]/description=Not an annotation
+statictest:heredoc() {
+EOT
+}
+
+if true; then
+  statictest:conditional() { echo "conditional"; }
+fi